@@ -1,14 +1,24 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tariktz/gopherseo/internal/auth"
+	"github.com/tariktz/gopherseo/internal/config"
 	"github.com/tariktz/gopherseo/internal/crawler"
+	"github.com/tariktz/gopherseo/internal/images"
+	"github.com/tariktz/gopherseo/internal/lastmod"
 	"github.com/tariktz/gopherseo/internal/output"
+	"github.com/tariktz/gopherseo/internal/sitemap"
+	"github.com/tariktz/gopherseo/internal/throttle"
 )
 
 type crawlOptions struct {
@@ -20,6 +30,55 @@ type crawlOptions struct {
 	userAgent       string
 	excludePatterns []string
 	timeout         time.Duration
+	soft404         bool
+	auditImages     bool
+	imageMaxBytes   int64
+	imageOutput     string
+	sitemapImages   bool
+	imageHosts      []string
+	imagesMainOnly  bool
+	videoSitemap    string
+	newsSitemap     string
+	mediaOutput     string
+	hreflangOutput  string
+	sitemapHreflang bool
+	canonicalOnly   bool
+	clustersOutput  string
+	lastmodStore    string
+	lastmodOutput   string
+	lastmodConflict time.Duration
+	lastmodSources  []string
+	lastmodSitemap  string
+	precision       string
+	timezone        string
+	configPath      string
+	robotsOutput    string
+	robotsInlinks   int
+	delay           time.Duration
+	jitter          time.Duration
+	ignoreDelay     bool
+	adaptive        bool
+	retries         int
+	retryAttempts   int
+	retryBackoff    time.Duration
+	retryStatuses   []int
+	retryErrors     []string
+	maxBodySize     int
+	basicAuth       string
+	bearerToken     string
+	headers         []string
+	cookieFile      string
+	loginURL        string
+	loginFields     []string
+	resolve         []string
+	caFile          string
+	clientCert      string
+	clientKey       string
+	insecure        bool
+	tlsOutput       string
+	certExpiryDays  int
+	proxies         []string
+	proxyFile       string
 }
 
 func init() {
@@ -32,6 +91,88 @@ func init() {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			rootURL := strings.TrimSpace(args[0])
 
+			lastmodSources := make([]lastmod.Source, 0, len(opts.lastmodSources))
+			for _, name := range opts.lastmodSources {
+				source, err := lastmod.ParseSource(name)
+				if err != nil {
+					return err
+				}
+				lastmodSources = append(lastmodSources, source)
+			}
+
+			cfg, err := config.Load(opts.configPath, !cmd.Flags().Changed("config"))
+			if err != nil {
+				return err
+			}
+
+			precision, err := lastmod.ParsePrecision(opts.precision)
+			if err != nil {
+				return err
+			}
+			location, err := time.LoadLocation(opts.timezone)
+			if err != nil {
+				return fmt.Errorf("invalid --timezone: %w", err)
+			}
+
+			var sitemapLastmod map[string]time.Time
+			if opts.lastmodSitemap != "" {
+				urls, err := sitemap.Load(&http.Client{Timeout: opts.timeout}, opts.lastmodSitemap)
+				if err != nil {
+					return err
+				}
+				sitemapLastmod = sitemap.LastModified(urls)
+			}
+
+			retryPolicy := crawler.DefaultRetryPolicy()
+			retryPolicy.Attempts = opts.retryAttempts
+			retryPolicy.Backoff = opts.retryBackoff
+			retryPolicy.Statuses = opts.retryStatuses
+			retryPolicy.Errors = make([]crawler.ErrorClass, 0, len(opts.retryErrors))
+			for _, name := range opts.retryErrors {
+				class, err := crawler.ParseErrorClass(name)
+				if err != nil {
+					return fmt.Errorf("invalid --retry-errors: %w", err)
+				}
+				retryPolicy.Errors = append(retryPolicy.Errors, class)
+			}
+
+			authOpts, err := authOptions(opts)
+			if err != nil {
+				return err
+			}
+
+			resolve := make([]crawler.Resolve, 0, len(opts.resolve))
+			for _, value := range opts.resolve {
+				r, err := crawler.ParseResolve(value)
+				if err != nil {
+					return fmt.Errorf("invalid --resolve: %w", err)
+				}
+				resolve = append(resolve, r)
+			}
+
+			proxies := opts.proxies
+			if opts.proxyFile != "" {
+				fromFile, err := readURLFile(opts.proxyFile)
+				if err != nil {
+					return err
+				}
+				proxies = append(proxies, fromFile...)
+			}
+
+			if opts.insecure {
+				fmt.Fprintln(os.Stderr, "Warning: TLS certificate verification is disabled (--insecure)")
+			}
+
+			rateLimitRetries := opts.retries
+			if rateLimitRetries == 0 {
+				// crawler.Options treats zero as the default.
+				rateLimitRetries = -1
+			}
+
+			// progressMu keeps throttle messages from interleaving with the
+			// spinner line.
+			var progressMu sync.Mutex
+
 			spinnerStop := make(chan struct{})
 			spinnerDone := make(chan struct{})
 			go func() {
@@ -46,19 +187,52 @@ func init() {
 						fmt.Fprint(os.Stderr, "\r")
 						return
 					case <-ticker.C:
+						progressMu.Lock()
 						fmt.Fprintf(os.Stderr, "\rCrawling... %c", frames[i%len(frames)])
+						progressMu.Unlock()
 						i++
 					}
 				}
 			}()
 
 			result, err := crawler.Crawl(crawler.Options{
-				RootURL:         rootURL,
-				MaxDepth:        opts.depth,
-				Threads:         opts.threads,
-				UserAgent:       opts.userAgent,
-				ExcludePatterns: opts.excludePatterns,
-				RequestTimeout:  opts.timeout,
+				RootURL:                  rootURL,
+				MaxDepth:                 opts.depth,
+				Threads:                  opts.threads,
+				UserAgent:                opts.userAgent,
+				ExcludePatterns:          opts.excludePatterns,
+				RequestTimeout:           opts.timeout,
+				DetectSoft404:            opts.soft404,
+				AuditImages:              opts.auditImages,
+				ImageMaxBytes:            opts.imageMaxBytes,
+				CanonicalOnlySitemap:     opts.canonicalOnly,
+				LastmodStorePath:         opts.lastmodStore,
+				LastmodConflictThreshold: opts.lastmodConflict,
+				LastmodSources:           lastmodSources,
+				SitemapLastmod:           sitemapLastmod,
+				Location:                 location,
+				RobotsInlinkThreshold:    opts.robotsInlinks,
+				Delay:                    opts.delay,
+				Jitter:                   opts.jitter,
+				IgnoreCrawlDelay:         opts.ignoreDelay,
+				AdaptiveConcurrency:      opts.adaptive,
+				RateLimitRetries:         rateLimitRetries,
+				MaxBodySize:              opts.maxBodySize,
+				Retry:                    retryPolicy,
+				Auth:                     authOpts,
+				Resolve:                  resolve,
+				Proxies:                  proxies,
+				TLS: crawler.TLSOptions{
+					CAFile:             opts.caFile,
+					CertFile:           opts.clientCert,
+					KeyFile:            opts.clientKey,
+					InsecureSkipVerify: opts.insecure,
+				},
+				OnThrottle: func(e throttle.Event) {
+					progressMu.Lock()
+					fmt.Fprintf(os.Stderr, "\rThrottle: %s\n", e.Detail)
+					progressMu.Unlock()
+				},
 			})
 			close(spinnerStop)
 			<-spinnerDone
@@ -66,7 +240,21 @@ func init() {
 				return err
 			}
 
-			if err := output.WriteSitemap(opts.output, result.ValidURLs, result.LastModified); err != nil {
+			sitemapOpts := output.SitemapOptions{
+				LastModified:     lastmod.Times(result.LastModified),
+				LastmodPrecision: precision,
+				Location:         location,
+			}
+			if len(cfg.Sitemap.Rules) > 0 {
+				sitemapOpts.Changefreq, sitemapOpts.Priority = sitemap.Assign(cfg.Sitemap.Rules, result.SitemapURLs, result.DepthByURL, result.LastModified, time.Now())
+			}
+			if opts.sitemapHreflang {
+				sitemapOpts.Alternates = result.HreflangByPage
+			}
+			if opts.sitemapImages {
+				sitemapOpts.Images = images.SitemapImages(result.ImagesByPage, opts.imageHosts, opts.imagesMainOnly)
+			}
+			if err := output.WriteSitemapWithOptions(opts.output, result.SitemapURLs, sitemapOpts); err != nil {
 				return err
 			}
 
@@ -78,17 +266,126 @@ func init() {
 				return err
 			}
 
+			if opts.clustersOutput != "" {
+				if err := output.WriteCanonicalClusters(opts.clustersOutput, result.CanonicalClusters); err != nil {
+					return err
+				}
+			}
+
+			if opts.lastmodOutput != "" {
+				if err := output.WriteLastmodReport(opts.lastmodOutput, result.LastmodReport); err != nil {
+					return err
+				}
+			}
+
+			if opts.hreflangOutput != "" {
+				if err := output.WriteHreflangIssues(opts.hreflangOutput, result.HreflangIssues); err != nil {
+					return err
+				}
+			}
+
+			if opts.robotsOutput != "" {
+				if err := output.WriteRobotsReport(opts.robotsOutput, result.RobotsTxt, result.RobotsBlocked); err != nil {
+					return err
+				}
+			}
+
+			certExpiryWindow := time.Duration(opts.certExpiryDays) * 24 * time.Hour
+			if opts.tlsOutput != "" {
+				if err := output.WriteCertificateReport(opts.tlsOutput, result.Certificates, certExpiryWindow, time.Now()); err != nil {
+					return err
+				}
+			}
+
+			if opts.videoSitemap != "" {
+				if err := output.WriteVideoSitemap(opts.videoSitemap, result.VideosByPage); err != nil {
+					return err
+				}
+			}
+			if opts.newsSitemap != "" {
+				if err := output.WriteNewsSitemap(opts.newsSitemap, result.NewsByPage, time.Now()); err != nil {
+					return err
+				}
+			}
+			writeMediaReport := opts.videoSitemap != "" || opts.newsSitemap != ""
+			if writeMediaReport {
+				if err := output.WriteMediaIssues(opts.mediaOutput, result.MediaIssues); err != nil {
+					return err
+				}
+			}
+
+			if opts.auditImages {
+				if err := output.WriteImageReport(opts.imageOutput, result.ImageAudits); err != nil {
+					return err
+				}
+			}
+
 			fmt.Printf("\nCrawl complete\n")
 			fmt.Printf("  Discovered:    %d\n", result.Discovered)
 			fmt.Printf("  Valid URLs:    %d\n", len(result.ValidURLs))
+			fmt.Printf("  Sitemap URLs:  %d\n", len(result.SitemapURLs))
 			fmt.Printf("  Broken links:  %d\n", len(result.BrokenLinks))
 			fmt.Printf("  Excluded URLs: %d\n", result.ExcludedURLs)
+			fmt.Printf("  Soft 404s:     %d\n", len(result.Soft404Pages))
 			fmt.Printf("  Canonical issues: %d\n", len(result.CanonicalIssues))
 			fmt.Printf("  Missing canonical: %d\n", len(result.MissingCanonicalPages))
 			fmt.Printf("  Multiple canonical: %d\n", len(result.MultipleCanonicalPages))
+			fmt.Printf("  Canonical clusters: %d\n", len(result.CanonicalClusters))
+			fmt.Printf("  Hreflang issues: %d\n", len(result.HreflangIssues))
+			flaggedBlocks := 0
+			for _, link := range result.RobotsBlocked {
+				if link.Flagged {
+					flaggedBlocks++
+				}
+			}
+			fmt.Printf("  Robots-blocked links: %d (%d flagged)\n", len(result.RobotsBlocked), flaggedBlocks)
+			if result.RobotsTxt != nil && len(result.RobotsTxt.Problems) > 0 {
+				fmt.Printf("  robots.txt ignored lines: %d\n", len(result.RobotsTxt.Problems))
+			}
+			expiringCerts := 0
+			for _, cert := range result.Certificates {
+				if time.Until(cert.NotAfter) < certExpiryWindow {
+					expiringCerts++
+				}
+			}
+			fmt.Printf("  TLS certificates: %d (%d expiring within %d days)\n", len(result.Certificates), expiringCerts, opts.certExpiryDays)
+			fmt.Printf("  Lastmod issues: %d (%.1f%% fallback)\n", len(result.LastmodReport.Issues), result.LastmodReport.FallbackShare()*100)
+			if opts.auditImages {
+				fmt.Printf("  Images audited: %d\n", len(result.ImageAudits))
+			}
+			if writeMediaReport {
+				fmt.Printf("  Media issues: %d\n", len(result.MediaIssues))
+			}
 			fmt.Printf("\nSitemap written to %s\n", opts.output)
 			fmt.Printf("Broken-link task report written to %s\n", opts.issuesOutput)
 			fmt.Printf("Canonical issue report written to %s\n", opts.canonicalOutput)
+			if opts.clustersOutput != "" {
+				fmt.Printf("Canonical cluster report written to %s\n", opts.clustersOutput)
+			}
+			if opts.hreflangOutput != "" {
+				fmt.Printf("Hreflang issue report written to %s\n", opts.hreflangOutput)
+			}
+			if opts.lastmodOutput != "" {
+				fmt.Printf("Lastmod report written to %s\n", opts.lastmodOutput)
+			}
+			if opts.robotsOutput != "" {
+				fmt.Printf("robots.txt report written to %s\n", opts.robotsOutput)
+			}
+			if opts.tlsOutput != "" {
+				fmt.Printf("TLS certificate report written to %s\n", opts.tlsOutput)
+			}
+			if opts.auditImages {
+				fmt.Printf("Image report written to %s\n", opts.imageOutput)
+			}
+			if opts.videoSitemap != "" {
+				fmt.Printf("Video sitemap written to %s\n", opts.videoSitemap)
+			}
+			if opts.newsSitemap != "" {
+				fmt.Printf("News sitemap written to %s\n", opts.newsSitemap)
+			}
+			if writeMediaReport {
+				fmt.Printf("Media issue report written to %s\n", opts.mediaOutput)
+			}
 
 			if len(result.BrokenLinks) > 0 {
 				fmt.Fprintf(os.Stderr, "\nBroken links found (%d):\n", len(result.BrokenLinks))
@@ -101,14 +398,109 @@ func init() {
 		},
 	}
 
+	crawlCmd.Flags().StringVar(&opts.configPath, "config", config.DefaultPath, "Project config file (JSON) with sitemap changefreq/priority rules")
 	crawlCmd.Flags().StringVarP(&opts.output, "output", "o", "./sitemap.xml", "Output sitemap file path")
 	crawlCmd.Flags().StringVar(&opts.issuesOutput, "issues-output", "./broken-link-tasks.md", "Output file for broken-link cleanup tasks")
 	crawlCmd.Flags().StringVar(&opts.canonicalOutput, "canonical-report-output", "./canonical-issues.md", "Output file for canonical URL issues")
+	crawlCmd.Flags().StringVar(&opts.clustersOutput, "canonical-clusters-output", "", "Output file for the canonical clusters report (empty = disabled)")
+	crawlCmd.Flags().StringVar(&opts.hreflangOutput, "hreflang-report-output", "", "Output file for hreflang issues (empty = disabled)")
+	crawlCmd.Flags().StringVar(&opts.robotsOutput, "robots-report-output", "", "Output file for the robots.txt analysis and robots-blocked links (empty = disabled)")
+	crawlCmd.Flags().IntVar(&opts.robotsInlinks, "robots-inlink-threshold", crawler.DefaultRobotsInlinkThreshold, "Number of linking pages from which a robots-blocked URL is flagged")
+	crawlCmd.Flags().StringVar(&opts.tlsOutput, "tls-report-output", "", "Output file for the TLS certificates of crawled hosts (empty = disabled)")
+	crawlCmd.Flags().IntVar(&opts.certExpiryDays, "cert-expiry-days", 30, "Warn about TLS certificates expiring within this many days")
+	crawlCmd.Flags().StringVar(&opts.imageOutput, "image-report-output", "./image-issues.md", "Output file for image SEO issues (requires --audit-images)")
+	crawlCmd.Flags().StringVar(&opts.videoSitemap, "video-sitemap-output", "", "Output file for a video sitemap (empty = disabled)")
+	crawlCmd.Flags().StringVar(&opts.newsSitemap, "news-sitemap-output", "", "Output file for a news sitemap of articles from the last 48 hours (empty = disabled)")
+	crawlCmd.Flags().StringVar(&opts.mediaOutput, "media-report-output", "./media-issues.md", "Output file for video/news sitemap issues (written with either media sitemap)")
 	crawlCmd.Flags().IntVar(&opts.threads, "threads", 5, "Maximum concurrent crawler workers")
 	crawlCmd.Flags().IntVar(&opts.depth, "depth", 0, "Max crawl depth (0 = unlimited)")
 	crawlCmd.Flags().StringVar(&opts.userAgent, "user-agent", "GopherSEO-Bot/1.0", "Crawler user-agent")
 	crawlCmd.Flags().StringSliceVar(&opts.excludePatterns, "exclude", []string{}, "Glob pattern to skip (repeatable)")
 	crawlCmd.Flags().DurationVar(&opts.timeout, "timeout", 30*time.Second, "Timeout per HTTP request (e.g. 10s, 1m)")
+	crawlCmd.Flags().DurationVar(&opts.delay, "delay", 0, "Minimum delay between requests to the site (a longer robots.txt Crawl-delay wins)")
+	crawlCmd.Flags().DurationVar(&opts.jitter, "jitter", 0, "Random extra delay of up to this duration added to every request")
+	crawlCmd.Flags().BoolVar(&opts.ignoreDelay, "ignore-crawl-delay", false, "Ignore the robots.txt Crawl-delay directive")
+	crawlCmd.Flags().BoolVar(&opts.adaptive, "adaptive-concurrency", false, "Lower parallelism while response times rise and after 429/503 responses")
+	crawlCmd.Flags().IntVar(&opts.retries, "rate-limit-retries", throttle.DefaultMaxRetries, "Retries for 429/503 responses after honoring Retry-After (0 = disabled)")
+	crawlCmd.Flags().IntVar(&opts.retryAttempts, "retry-attempts", crawler.DefaultRetryPolicy().Attempts, "Maximum requests per URL before it is reported as broken (1 = no retries)")
+	crawlCmd.Flags().DurationVar(&opts.retryBackoff, "retry-backoff", crawler.DefaultRetryPolicy().Backoff, "Wait before the first retry; doubles for every further retry (max 30s)")
+	crawlCmd.Flags().IntSliceVar(&opts.retryStatuses, "retry-status", crawler.DefaultRetryPolicy().Statuses, "HTTP status codes that are retried (repeatable)")
+	defaultRetryErrors := make([]string, 0, len(crawler.DefaultRetryPolicy().Errors))
+	for _, class := range crawler.DefaultRetryPolicy().Errors {
+		defaultRetryErrors = append(defaultRetryErrors, string(class))
+	}
+	crawlCmd.Flags().StringSliceVar(&opts.retryErrors, "retry-errors", defaultRetryErrors, "Request failure classes that are retried: dns, refused, tls, timeout, too_many_redirects, body_too_large, connection, other (repeatable)")
+	crawlCmd.Flags().IntVar(&opts.maxBodySize, "max-body-size", 0, "Largest response body in bytes; larger responses are reported as broken (body_too_large). 0 = no limit check")
+	crawlCmd.Flags().StringVar(&opts.basicAuth, "basic-auth", "", "HTTP basic auth credentials as user:password, sent to the root host only")
+	crawlCmd.Flags().StringVar(&opts.bearerToken, "bearer-token", "", "Bearer token sent in the Authorization header to the root host only")
+	crawlCmd.Flags().StringArrayVarP(&opts.headers, "header", "H", nil, "Extra request header as \"Name: value\", sent to the root host only (repeatable)")
+	crawlCmd.Flags().StringVar(&opts.cookieFile, "cookies", "", "Netscape cookies.txt file whose cookies for the root host are sent")
+	crawlCmd.Flags().StringVar(&opts.loginURL, "login-url", "", "Form login URL on the root host; credentials are POSTed before the crawl and the session cookies kept")
+	crawlCmd.Flags().StringArrayVar(&opts.loginFields, "login-field", nil, "Login form field as name=value (repeatable, requires --login-url)")
+	crawlCmd.Flags().StringArrayVar(&opts.resolve, "resolve", nil, "Connect to an address instead of DNS for host:port, as host:port:address[,address] (repeatable)")
+	crawlCmd.Flags().StringArrayVar(&opts.proxies, "proxy", nil, "Proxy URL (http://, https:// or socks5://); several are rotated per request, NO_PROXY hosts are reached directly (repeatable, default HTTP_PROXY/HTTPS_PROXY/NO_PROXY)")
+	crawlCmd.Flags().StringVar(&opts.proxyFile, "proxy-file", "", "File with proxy URLs to rotate, one per line")
+	crawlCmd.Flags().StringVar(&opts.caFile, "ca-file", "", "PEM bundle of extra root CAs to trust, e.g. a private CA")
+	crawlCmd.Flags().StringVar(&opts.clientCert, "client-cert", "", "PEM client certificate for mutual TLS (requires --client-key)")
+	crawlCmd.Flags().StringVar(&opts.clientKey, "client-key", "", "PEM private key of --client-cert")
+	crawlCmd.Flags().BoolVar(&opts.insecure, "insecure", false, "Skip TLS certificate verification (certificates are still reported)")
+	crawlCmd.Flags().BoolVar(&opts.soft404, "detect-soft-404", false, "Probe the site's error page and report 200 responses that match it as soft 404s")
+	crawlCmd.Flags().BoolVar(&opts.auditImages, "audit-images", false, "Fetch every embedded image and audit alt text, dimensions and weight")
+	crawlCmd.Flags().StringVar(&opts.lastmodStore, "lastmod-store", "", "JSON content-hash store that keeps lastmod stable for unchanged pages across crawls (empty = disabled)")
+	crawlCmd.Flags().StringVar(&opts.lastmodOutput, "lastmod-report-output", "", "Output file for lastmod source conflicts, future dates and fallback share (empty = disabled)")
+	crawlCmd.Flags().DurationVar(&opts.lastmodConflict, "lastmod-conflict-threshold", lastmod.DefaultConflictThreshold, "Spread between a page's lastmod sources above which they are reported as conflicting")
+	crawlCmd.Flags().StringSliceVar(&opts.lastmodSources, "lastmod-source", nil, "Enabled lastmod sources in priority order: jsonld, meta, header, microdata, dublin_core, time, sitemap (repeatable; default all in this order)")
+	crawlCmd.Flags().StringVar(&opts.lastmodSitemap, "lastmod-sitemap", "", "Existing sitemap file or URL whose <lastmod> values feed the sitemap lastmod source")
+	crawlCmd.Flags().StringVar(&opts.precision, "lastmod-precision", string(lastmod.PrecisionDate), "Sitemap <lastmod> precision: date, minute or second")
+	crawlCmd.Flags().StringVar(&opts.timezone, "timezone", "UTC", "IANA time zone for lastmod values without an offset and for sitemap output (e.g. Europe/Berlin)")
+	crawlCmd.Flags().BoolVar(&opts.canonicalOnly, "sitemap-canonical-only", false, "Leave pages whose canonical points elsewhere out of the sitemap")
+	crawlCmd.Flags().BoolVar(&opts.sitemapHreflang, "sitemap-hreflang", false, "Add <xhtml:link rel=\"alternate\"> hreflang entries to the sitemap")
+	crawlCmd.Flags().BoolVar(&opts.sitemapImages, "sitemap-images", false, "Add <image:image> entries for images found on each page")
+	crawlCmd.Flags().StringSliceVar(&opts.imageHosts, "sitemap-image-host", []string{}, "Additional image host (e.g. a CDN) allowed in the image sitemap (repeatable)")
+	crawlCmd.Flags().BoolVar(&opts.imagesMainOnly, "sitemap-images-main-only", false, "Only list images inside <main>, <article> or role=\"main\" in the image sitemap")
+	crawlCmd.Flags().Int64Var(&opts.imageMaxBytes, "image-max-bytes", 200*1024, "Byte budget above which an image is reported as oversize (0 = no limit)")
 
 	rootCmd.AddCommand(crawlCmd)
 }
+
+// authOptions builds the crawler credentials from the auth flags.
+func authOptions(o *crawlOptions) (auth.Options, error) {
+	authOpts := auth.Options{BearerToken: o.bearerToken, CookieFile: o.cookieFile}
+
+	if o.basicAuth != "" {
+		user, password, ok := strings.Cut(o.basicAuth, ":")
+		if !ok {
+			return auth.Options{}, fmt.Errorf("invalid --basic-auth %q: expected user:password", o.basicAuth)
+		}
+		authOpts.Username = user
+		authOpts.Password = password
+	}
+
+	if len(o.headers) > 0 {
+		authOpts.Header = make(http.Header, len(o.headers))
+		for _, line := range o.headers {
+			name, value, err := auth.ParseHeader(line)
+			if err != nil {
+				return auth.Options{}, fmt.Errorf("invalid --header: %w", err)
+			}
+			authOpts.Header.Add(name, value)
+		}
+	}
+
+	if o.loginURL == "" {
+		if len(o.loginFields) > 0 {
+			return auth.Options{}, errors.New("--login-field requires --login-url")
+		}
+		return authOpts, nil
+	}
+	fields := make(url.Values, len(o.loginFields))
+	for _, field := range o.loginFields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return auth.Options{}, fmt.Errorf("invalid --login-field %q: expected name=value", field)
+		}
+		fields.Add(name, value)
+	}
+	authOpts.Login = &auth.FormLogin{URL: o.loginURL, Fields: fields}
+	return authOpts, nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tariktz/gopherseo/internal/robots"
+	"github.com/tariktz/gopherseo/internal/sitemap"
+)
+
+type robotsTestOptions struct {
+	agent       string
+	sitemap     string
+	urlFile     string
+	blockedOnly bool
+	timeout     time.Duration
+}
+
+func init() {
+	robotsCmd := &cobra.Command{
+		Use:   "robots",
+		Short: "Work with robots.txt files",
+	}
+
+	testOpts := robotsTestOptions{}
+	testCmd := &cobra.Command{
+		Use:   "test <robots-file-or-url> [url...]",
+		Short: "Check URLs against a robots.txt",
+		Long: `Evaluate URLs against a robots.txt file or URL for one user agent and print
+each allow/disallow decision with the rule and line number that made it.
+Google's precedence applies: the most specific user-agent group, then the
+longest matching rule, with Allow winning ties.
+
+URLs can be given as arguments, read from a file (--url-file, one per line)
+or taken from a sitemap (--sitemap), for example the sitemap written by a
+previous crawl, to see which indexed pages a robots.txt change would block.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &http.Client{Timeout: testOpts.timeout}
+
+			file, err := robots.Load(client, strings.TrimSpace(args[0]), testOpts.agent)
+			if err != nil {
+				return err
+			}
+
+			urls := append([]string{}, args[1:]...)
+			if testOpts.urlFile != "" {
+				fromFile, err := readURLFile(testOpts.urlFile)
+				if err != nil {
+					return err
+				}
+				urls = append(urls, fromFile...)
+			}
+			if testOpts.sitemap != "" {
+				entries, err := sitemap.Load(client, testOpts.sitemap)
+				if err != nil {
+					return err
+				}
+				for _, entry := range entries {
+					urls = append(urls, entry.Loc)
+				}
+			}
+			if len(urls) == 0 {
+				return fmt.Errorf("no URLs to test: pass URLs as arguments, --url-file or --sitemap")
+			}
+
+			disallowed := 0
+			for _, u := range urls {
+				match := file.Match(testOpts.agent, u)
+				if !match.Allowed {
+					disallowed++
+				} else if testOpts.blockedOnly {
+					continue
+				}
+
+				decision := "ALLOW   "
+				if !match.Allowed {
+					decision = "DISALLOW"
+				}
+				reason := "no matching rule"
+				if match.Rule != nil {
+					reason = fmt.Sprintf("line %d: %s", match.Rule.Line, match.Rule)
+				}
+				fmt.Printf("%s %s  (%s)\n", decision, u, reason)
+			}
+
+			for _, problem := range file.Problems {
+				fmt.Fprintf(os.Stderr, "robots.txt line %d: %s: %s\n", problem.Line, problem.Type, problem.Detail)
+			}
+
+			fmt.Printf("\nrobots.txt test complete\n")
+			fmt.Printf("  Agent:      %s\n", testOpts.agent)
+			fmt.Printf("  URLs:       %d\n", len(urls))
+			fmt.Printf("  Allowed:    %d\n", len(urls)-disallowed)
+			fmt.Printf("  Disallowed: %d\n", disallowed)
+			return nil
+		},
+	}
+	testCmd.Flags().StringVar(&testOpts.agent, "agent", "*", "User agent to evaluate (e.g. Googlebot)")
+	testCmd.Flags().StringVar(&testOpts.sitemap, "sitemap", "", "Also test every URL listed in this sitemap file or URL")
+	testCmd.Flags().StringVar(&testOpts.urlFile, "url-file", "", "Also test URLs from this file, one per line")
+	testCmd.Flags().BoolVar(&testOpts.blockedOnly, "blocked-only", false, "Only print disallowed URLs")
+	testCmd.Flags().DurationVar(&testOpts.timeout, "timeout", 10*time.Second, "HTTP request timeout")
+
+	robotsCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(robotsCmd)
+}
+
+// readURLFile returns the non-empty lines of path, skipping # comments.
+func readURLFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open url file: %w", err)
+	}
+	defer f.Close()
+
+	urls := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read url file: %w", err)
+	}
+	return urls, nil
+}
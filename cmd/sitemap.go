@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tariktz/gopherseo/internal/output"
+	"github.com/tariktz/gopherseo/internal/sitemap"
+)
+
+type sitemapValidateOptions struct {
+	fetch     bool
+	threads   int
+	userAgent string
+	timeout   time.Duration
+	output    string
+}
+
+type sitemapDiffOptions struct {
+	timeout time.Duration
+	output  string
+}
+
+func init() {
+	sitemapCmd := &cobra.Command{
+		Use:   "sitemap",
+		Short: "Work with existing sitemaps",
+	}
+
+	validateOpts := sitemapValidateOptions{}
+	validateCmd := &cobra.Command{
+		Use:   "validate <file-or-url>",
+		Short: "Lint a sitemap or sitemap index",
+		Long: `Validate a sitemap or sitemap index (plain or gzip) from a file or URL:
+root element and namespaces, size and URL limits, <loc> syntax, lastmod
+format, changefreq/priority values, duplicate and cross-host locs. With
+--fetch, every <loc> is requested and non-200, redirecting, noindex and
+non-canonical entries are reported. Exits with an error when issues are found.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location := strings.TrimSpace(args[0])
+
+			report, err := sitemap.Validate(&http.Client{Timeout: validateOpts.timeout}, location, sitemap.ValidateOptions{
+				FetchLocs: validateOpts.fetch,
+				UserAgent: validateOpts.userAgent,
+				Threads:   validateOpts.threads,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, issue := range report.Issues {
+				subject := issue.Sitemap
+				if issue.Loc != "" {
+					subject = issue.Loc
+				}
+				fmt.Printf("%s: %s: %s\n", issue.Type, subject, issue.Detail)
+			}
+
+			fmt.Printf("\nSitemap validation complete\n")
+			fmt.Printf("  Sitemaps: %d\n", len(report.Sitemaps))
+			fmt.Printf("  URLs:     %d\n", report.URLCount)
+			fmt.Printf("  Issues:   %d\n", len(report.Issues))
+
+			if validateOpts.output != "" {
+				if err := output.WriteSitemapValidation(validateOpts.output, report); err != nil {
+					return err
+				}
+				fmt.Printf("\nValidation report written to %s\n", validateOpts.output)
+			}
+
+			if len(report.Issues) > 0 {
+				return fmt.Errorf("sitemap validation found %d issue(s)", len(report.Issues))
+			}
+			return nil
+		},
+	}
+	validateCmd.Flags().BoolVar(&validateOpts.fetch, "fetch", false, "Request every <loc> and report non-200, redirecting, noindex and non-canonical entries")
+	validateCmd.Flags().IntVar(&validateOpts.threads, "threads", 5, "Maximum concurrent requests with --fetch")
+	validateCmd.Flags().StringVar(&validateOpts.userAgent, "user-agent", "GopherSEO-Bot/1.0", "User-Agent sent with --fetch")
+	validateCmd.Flags().DurationVar(&validateOpts.timeout, "timeout", 10*time.Second, "HTTP request timeout")
+	validateCmd.Flags().StringVarP(&validateOpts.output, "output", "o", "", "Optional Markdown report path")
+
+	diffOpts := sitemapDiffOptions{}
+	diffCmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare two sitemaps",
+		Long: `Compare two sitemaps or sitemap indexes (files or URLs, plain or gzip) and
+report URLs added and removed, lastmod changes, and counts per top-level
+directory. Typical use is checking a regenerated sitemap against the live one
+before deploying it.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &http.Client{Timeout: diffOpts.timeout}
+
+			oldURLs, err := sitemap.Load(client, strings.TrimSpace(args[0]))
+			if err != nil {
+				return fmt.Errorf("load old sitemap: %w", err)
+			}
+			newURLs, err := sitemap.Load(client, strings.TrimSpace(args[1]))
+			if err != nil {
+				return fmt.Errorf("load new sitemap: %w", err)
+			}
+
+			diff := sitemap.Compare(oldURLs, newURLs)
+
+			for _, loc := range diff.Added {
+				fmt.Printf("+ %s\n", loc)
+			}
+			for _, loc := range diff.Removed {
+				fmt.Printf("- %s\n", loc)
+			}
+			for _, change := range diff.Changed {
+				fmt.Printf("~ %s (%s -> %s)\n", change.Loc, change.Old, change.New)
+			}
+
+			fmt.Printf("\nSitemap diff complete\n")
+			fmt.Printf("  Old URLs:         %d\n", diff.OldCount)
+			fmt.Printf("  New URLs:         %d\n", diff.NewCount)
+			fmt.Printf("  Added:            %d\n", len(diff.Added))
+			fmt.Printf("  Removed:          %d\n", len(diff.Removed))
+			fmt.Printf("  Lastmod changed:  %d\n", len(diff.Changed))
+			if len(diff.Directories) > 0 {
+				fmt.Printf("\n  By directory (added/removed/changed):\n")
+				for _, dir := range diff.Directories {
+					fmt.Printf("    %-30s +%d -%d ~%d\n", dir.Directory, dir.Added, dir.Removed, dir.Changed)
+				}
+			}
+
+			if diffOpts.output != "" {
+				if err := output.WriteSitemapDiff(diffOpts.output, diff); err != nil {
+					return err
+				}
+				fmt.Printf("\nDiff report written to %s\n", diffOpts.output)
+			}
+			return nil
+		},
+	}
+	diffCmd.Flags().DurationVar(&diffOpts.timeout, "timeout", 10*time.Second, "HTTP request timeout")
+	diffCmd.Flags().StringVarP(&diffOpts.output, "output", "o", "", "Optional Markdown report path")
+
+	sitemapCmd.AddCommand(validateCmd)
+	sitemapCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(sitemapCmd)
+}
@@ -0,0 +1,283 @@
+// Package auth adds credentials to crawler requests: HTTP basic or bearer
+// authentication, custom request headers, cookies imported from a Netscape
+// cookies.txt file and an optional form login. Credentials are only ever sent
+// to the crawl's root host.
+package auth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options configures the credentials used for a crawl. The zero value sends
+// no credentials.
+type Options struct {
+	// Username and Password enable HTTP basic authentication.
+	Username string
+	Password string
+	// BearerToken sends an "Authorization: Bearer" header. It cannot be
+	// combined with basic authentication.
+	BearerToken string
+	// Header is added to every request to the root host, replacing values
+	// set by the crawler such as User-Agent.
+	Header http.Header
+	// CookieFile is a Netscape cookies.txt file whose cookies for the root
+	// host are loaded into the crawl's cookie jar.
+	CookieFile string
+	// Login, when set, is performed before the crawl starts; the session
+	// cookies it receives are kept for the crawl.
+	Login *FormLogin
+}
+
+// FormLogin posts credentials to a login form.
+type FormLogin struct {
+	// URL is the form's action URL. It must be on the root host.
+	URL string
+	// Fields are the form fields, e.g. username and password.
+	Fields url.Values
+}
+
+// Transport is an http.RoundTripper that adds the configured credentials to
+// requests for the root host and passes all other requests through
+// unchanged, so redirects and links to other hosts never see them.
+type Transport struct {
+	// Base performs the requests. Nil uses http.DefaultTransport.
+	Base http.RoundTripper
+
+	root    *url.URL
+	opts    Options
+	enabled bool
+}
+
+// NewTransport returns a Transport that adds the credentials in opts to
+// requests for root's scheme, host and port.
+func NewTransport(base http.RoundTripper, root *url.URL, opts Options) (*Transport, error) {
+	if opts.BearerToken != "" && (opts.Username != "" || opts.Password != "") {
+		return nil, errors.New("basic and bearer authentication cannot be combined")
+	}
+	return &Transport{
+		Base:    base,
+		root:    root,
+		opts:    opts,
+		enabled: opts.Username != "" || opts.Password != "" || opts.BearerToken != "" || len(opts.Header) > 0,
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !t.enabled || !SameOrigin(t.root, req.URL) {
+		return base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(req.Context())
+	for name, values := range t.opts.Header {
+		req.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	switch {
+	case t.opts.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+t.opts.BearerToken)
+	case t.opts.Username != "" || t.opts.Password != "":
+		req.SetBasicAuth(t.opts.Username, t.opts.Password)
+	}
+	return base.RoundTrip(req)
+}
+
+// SameOrigin reports whether u has the scheme, host and port of root.
+// Credentials for an https root are therefore never sent over plain http.
+func SameOrigin(root, u *url.URL) bool {
+	return strings.EqualFold(root.Scheme, u.Scheme) &&
+		strings.EqualFold(root.Hostname(), u.Hostname()) &&
+		port(root) == port(u)
+}
+
+func port(u *url.URL) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+	if strings.EqualFold(u.Scheme, "https") {
+		return "443"
+	}
+	return "80"
+}
+
+// ParseHeader parses a "Name: value" header line as given on the command
+// line.
+func ParseHeader(line string) (string, string, error) {
+	name, value, ok := strings.Cut(line, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid header %q: expected \"Name: value\"", line)
+	}
+	return http.CanonicalHeaderKey(name), strings.TrimSpace(value), nil
+}
+
+// NewJar returns a cookie jar holding the cookies from opts.CookieFile that
+// apply to root. Imported cookies are stored as host-only cookies of root's
+// host, and the jar only returns cookies for root's origin, so neither
+// imported cookies nor Domain cookies set during the crawl (e.g. by Login)
+// are sent to sibling subdomains.
+func NewJar(root *url.URL, opts Options) (http.CookieJar, error) {
+	cookies, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("create cookie jar: %w", err)
+	}
+	jar := &originJar{CookieJar: cookies, root: root}
+	if opts.CookieFile == "" {
+		return jar, nil
+	}
+
+	f, err := os.Open(opts.CookieFile)
+	if err != nil {
+		return nil, fmt.Errorf("open cookie file: %w", err)
+	}
+	defer f.Close()
+
+	imported, err := ParseCookies(f, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("parse cookie file %s: %w", opts.CookieFile, err)
+	}
+
+	var matching []*http.Cookie
+	for _, cookie := range imported {
+		if cookieMatches(cookie, root) {
+			cookie.Domain = ""
+			matching = append(matching, cookie)
+		}
+	}
+	jar.SetCookies(root, matching)
+	return jar, nil
+}
+
+// originJar is a cookie jar that only returns cookies for requests to root's
+// origin.
+type originJar struct {
+	http.CookieJar
+	root *url.URL
+}
+
+// Cookies implements http.CookieJar.
+func (j *originJar) Cookies(u *url.URL) []*http.Cookie {
+	if !SameOrigin(j.root, u) {
+		return nil
+	}
+	return j.CookieJar.Cookies(u)
+}
+
+// cookieMatches reports whether a cookie from a cookies.txt file applies to
+// root. Path restrictions are left to the jar.
+func cookieMatches(cookie *http.Cookie, root *url.URL) bool {
+	host := strings.ToLower(root.Hostname())
+	domain := strings.ToLower(strings.TrimPrefix(cookie.Domain, "."))
+	if cookie.Secure && !strings.EqualFold(root.Scheme, "https") {
+		return false
+	}
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// ParseCookies reads cookies in the Netscape cookies.txt format used by curl
+// and browser export extensions: one cookie per line with the tab-separated
+// fields domain, include-subdomains flag, path, secure flag, expiry (Unix
+// seconds, 0 for a session cookie), name and value. Lines starting with "#"
+// are comments, except for the "#HttpOnly_" domain prefix. Cookies that
+// expired before now are skipped.
+func ParseCookies(r io.Reader, now time.Time) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line = rest
+			httpOnly = true
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) == 6 {
+			// Some exporters drop the value of empty cookies.
+			fields = append(fields, "")
+		}
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: expected 7 tab-separated fields, got %d", lineNo, len(fields))
+		}
+		expiry, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid expiry %q", lineNo, fields[4])
+		}
+
+		cookie := &http.Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if expiry > 0 {
+			cookie.Expires = time.Unix(expiry, 0)
+			if !cookie.Expires.After(now) {
+				continue
+			}
+		}
+		cookies = append(cookies, cookie)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cookies, nil
+}
+
+// Login posts login.Fields to login.URL with client, whose cookie jar keeps
+// the session cookies for the crawl. The login URL must share root's origin.
+// A response status of 400 or above is an error.
+func Login(client *http.Client, root *url.URL, userAgent string, login FormLogin) error {
+	loginURL, err := root.Parse(login.URL)
+	if err != nil {
+		return fmt.Errorf("invalid login URL: %w", err)
+	}
+	if !SameOrigin(root, loginURL) {
+		return fmt.Errorf("login URL %s is not on the crawl's root host %s", loginURL, root.Host)
+	}
+	if client.Jar == nil {
+		return errors.New("login requires a cookie jar")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, loginURL.String(), strings.NewReader(login.Fields.Encode()))
+	if err != nil {
+		return fmt.Errorf("create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("login: %s returned HTTP %d", loginURL, resp.StatusCode)
+	}
+	return nil
+}
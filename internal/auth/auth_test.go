@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse %q: %v", raw, err)
+	}
+	return u
+}
+
+func TestTransport_OnlyRootHostGetsCredentials(t *testing.T) {
+	var seen []string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		seen = append(seen, fmt.Sprintf("%s|%s|%s", req.URL.Host, req.Header.Get("Authorization"), req.Header.Get("X-Env")))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	root := mustParseURL(t, "https://staging.example.com/")
+	transport, err := NewTransport(base, root, Options{
+		Username: "user",
+		Password: "secret",
+		Header:   http.Header{"X-Env": {"staging"}},
+	})
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+
+	for _, raw := range []string{
+		"https://staging.example.com/page",
+		"https://STAGING.example.com:443/other",
+		"https://cdn.example.com/app.js",
+		"http://staging.example.com/insecure",
+		"https://staging.example.com:8443/admin",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, raw, nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip(%s): %v", raw, err)
+		}
+		if req.Header.Get("Authorization") != "" {
+			t.Errorf("RoundTrip modified the caller's request for %s", raw)
+		}
+	}
+
+	basic := "Basic dXNlcjpzZWNyZXQ="
+	want := []string{
+		"staging.example.com|" + basic + "|staging",
+		"STAGING.example.com:443|" + basic + "|staging",
+		"cdn.example.com||",
+		"staging.example.com||",
+		"staging.example.com:8443||",
+	}
+	if strings.Join(seen, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests =\n%s\nwant\n%s", strings.Join(seen, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTransport_Bearer(t *testing.T) {
+	var got string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get("Authorization")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	root := mustParseURL(t, "https://example.com")
+	transport, err := NewTransport(base, root, Options{BearerToken: "tok"})
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if got != "Bearer tok" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer tok")
+	}
+
+	if _, err := NewTransport(base, root, Options{Username: "u", BearerToken: "tok"}); err == nil {
+		t.Error("expected an error combining basic and bearer authentication")
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	name, value, err := ParseHeader("x-api-key:  abc:def ")
+	if err != nil || name != "X-Api-Key" || value != "abc:def" {
+		t.Errorf("ParseHeader = %q, %q, %v", name, value, err)
+	}
+	for _, bad := range []string{"no-colon", ": value", "bad name: x"} {
+		if _, _, err := ParseHeader(bad); err == nil {
+			t.Errorf("ParseHeader(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestParseCookies(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	data := strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		"",
+		".example.com\tTRUE\t/\tTRUE\t0\tsession\tabc",
+		"#HttpOnly_staging.example.com\tFALSE\t/admin\tFALSE\t1800000000\tadmin\tyes",
+		"example.com\tFALSE\t/\tFALSE\t1600000000\texpired\told",
+		"example.com\tFALSE\t/\tFALSE\t0\tempty",
+	}, "\n")
+
+	cookies, err := ParseCookies(strings.NewReader(data), now)
+	if err != nil {
+		t.Fatalf("ParseCookies: %v", err)
+	}
+	if len(cookies) != 3 {
+		t.Fatalf("expected 3 cookies, got %d: %+v", len(cookies), cookies)
+	}
+	if c := cookies[0]; c.Name != "session" || c.Value != "abc" || !c.Secure || !c.Expires.IsZero() {
+		t.Errorf("session cookie = %+v", c)
+	}
+	if c := cookies[1]; c.Name != "admin" || !c.HttpOnly || c.Path != "/admin" || c.Expires.Unix() != 1_800_000_000 {
+		t.Errorf("admin cookie = %+v", c)
+	}
+	if c := cookies[2]; c.Name != "empty" || c.Value != "" {
+		t.Errorf("empty cookie = %+v", c)
+	}
+
+	if _, err := ParseCookies(strings.NewReader("example.com\tFALSE\t/"), now); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected a line-numbered error, got %v", err)
+	}
+}
+
+func TestNewJar_OnlyRootHostCookies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	data := strings.Join([]string{
+		".example.com\tTRUE\t/\tFALSE\t0\tshared\t1",
+		"staging.example.com\tFALSE\t/\tFALSE\t0\tstaging\t2",
+		"other.example.org\tFALSE\t/\tFALSE\t0\tforeign\t3",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write cookies: %v", err)
+	}
+
+	root := mustParseURL(t, "https://staging.example.com/")
+	jar, err := NewJar(root, Options{CookieFile: path})
+	if err != nil {
+		t.Fatalf("NewJar: %v", err)
+	}
+
+	if got := cookieNames(jar.Cookies(root)); got != "shared,staging" {
+		t.Errorf("root cookies = %q, want shared,staging", got)
+	}
+	if got := cookieNames(jar.Cookies(mustParseURL(t, "https://www.example.com/"))); got != "" {
+		t.Errorf("sibling host cookies = %q, want none", got)
+	}
+
+	if _, err := NewJar(root, Options{CookieFile: filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("expected an error for a missing cookie file")
+	}
+}
+
+func TestNewJar_DomainCookiesStayOnRootOrigin(t *testing.T) {
+	root := mustParseURL(t, "https://staging.example.com/")
+	jar, err := NewJar(root, Options{})
+	if err != nil {
+		t.Fatalf("NewJar: %v", err)
+	}
+	jar.SetCookies(root, []*http.Cookie{{Name: "sid", Value: "42", Domain: "example.com", Path: "/"}})
+
+	if got := cookieNames(jar.Cookies(root)); got != "sid" {
+		t.Errorf("root cookies = %q, want sid", got)
+	}
+	for _, other := range []string{"https://www.example.com/", "http://staging.example.com/", "https://staging.example.com:8443/"} {
+		if got := cookieNames(jar.Cookies(mustParseURL(t, other))); got != "" {
+			t.Errorf("cookies for %s = %q, want none", other, got)
+		}
+	}
+}
+
+func TestLogin(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("sid"); err != nil {
+			http.Error(w, "not logged in", http.StatusUnauthorized)
+		}
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.PostFormValue("user") != "alice" || r.PostFormValue("pass") != "pw" {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "42", Path: "/"})
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	root := mustParseURL(t, ts.URL)
+	jar, err := NewJar(root, Options{})
+	if err != nil {
+		t.Fatalf("NewJar: %v", err)
+	}
+	client := &http.Client{Jar: jar}
+
+	fields := url.Values{"user": {"alice"}, "pass": {"pw"}}
+	if err := Login(client, root, "test-agent", FormLogin{URL: "/login", Fields: fields}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if got := cookieNames(jar.Cookies(root)); got != "sid" {
+		t.Errorf("cookies after login = %q, want sid", got)
+	}
+
+	bad := url.Values{"user": {"alice"}, "pass": {"wrong"}}
+	if err := Login(client, root, "", FormLogin{URL: "/login", Fields: bad}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected an HTTP 401 error, got %v", err)
+	}
+	if err := Login(client, root, "", FormLogin{URL: "https://sso.example.com/login", Fields: fields}); err == nil {
+		t.Error("expected an error for a login URL on another host")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func cookieNames(cookies []*http.Cookie) string {
+	names := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ",")
+}
@@ -1,22 +1,46 @@
 package canonical
 
 import (
+	"net/http"
 	"net/url"
 	"sort"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/tariktz/gopherseo/internal/linkheader"
 )
 
 // Info contains canonical tag extraction details for a crawled page.
 type Info struct {
-	PageURL      string
+	PageURL string
+	// CanonicalURL is the effective canonical: the HTML value when present,
+	// otherwise the value declared in the HTTP Link header.
 	CanonicalURL string
-	TagCount     int
-	Missing      bool
-	Multiple     bool
+	// HTMLCanonicalURL is the canonical declared by <link rel="canonical">.
+	HTMLCanonicalURL string
+	// HeaderCanonicalURL is the canonical declared by the HTTP
+	// `Link: <...>; rel="canonical"` header.
+	HeaderCanonicalURL string
+	// RawHref is the HTML canonical href exactly as written, before
+	// resolution and normalization.
+	RawHref string
+	// Relative reports whether RawHref was a relative reference.
+	Relative bool
+	// Placement records whether the HTML canonical sits in <head> or <body>.
+	Placement Placement
+	TagCount  int
+	Missing   bool
+	Multiple  bool
 }
 
+// Placement is the document section a canonical <link> was found in.
+type Placement string
+
+const (
+	PlacementHead Placement = "head"
+	PlacementBody Placement = "body"
+)
+
 // IssueType describes a canonical validation problem category.
 type IssueType string
 
@@ -26,8 +50,35 @@ const (
 	IssueTargetBroken   IssueType = "target_broken"
 	IssueTargetRedirect IssueType = "target_redirect"
 	IssueLoopOrChain    IssueType = "loop_or_chain"
+	// IssueHeaderHTMLMismatch is reported when the Link header and the HTML
+	// declare different canonical URLs.
+	IssueHeaderHTMLMismatch IssueType = "header_html_mismatch"
+	// Placement and syntax issues derived from the raw HTML canonical.
+	IssueCanonicalInBody IssueType = "canonical_in_body"
+	IssueRelativeURL     IssueType = "relative_url"
+	IssueQueryString     IssueType = "query_string"
+	IssueUppercaseHost   IssueType = "uppercase_host"
+	// Consistency issues comparing canonical targets with other crawl signals.
+	IssueTargetNoindex       IssueType = "target_noindex"
+	IssueTargetRobotsBlocked IssueType = "target_robots_blocked"
+	IssueTargetExcluded      IssueType = "target_excluded"
+	IssueSitemapNonCanonical IssueType = "sitemap_non_canonical"
 )
 
+// Signals carries crawl facts that ValidateConsistency checks canonical
+// targets against. Nil fields disable the corresponding check.
+type Signals struct {
+	// Noindex holds URLs that declared noindex via meta robots or
+	// X-Robots-Tag.
+	Noindex map[string]struct{}
+	// RobotsBlocked reports whether robots.txt disallows a URL.
+	RobotsBlocked func(string) bool
+	// Excluded reports whether a URL matches the crawl's exclusion rules.
+	Excluded func(string) bool
+	// SitemapURLs lists the URLs written to the sitemap.
+	SitemapURLs []string
+}
+
 // Issue represents a canonical validation finding for a page.
 type Issue struct {
 	PageURL      string
@@ -36,9 +87,29 @@ type Issue struct {
 	Detail       string
 }
 
+// Member is a page belonging to a canonical cluster.
+type Member struct {
+	URL string
+	// Inlinks is the number of distinct crawled pages linking to URL.
+	Inlinks int
+}
+
+// Cluster groups every crawled page that declares the same canonical target.
+type Cluster struct {
+	CanonicalURL string
+	// Status is the HTTP status of the canonical target, when crawled.
+	Status      int
+	StatusKnown bool
+	// Members lists the pages in the cluster, most linked first. The target
+	// itself is included when it declares a self-referencing canonical.
+	Members []Member
+}
+
 // Extract inspects a page document and extracts canonical link information.
 // It resolves relative canonical href values against pageURL and applies URL
-// normalization (strip fragments and trailing slash for non-root paths).
+// normalization (lower-case host, strip fragments and trailing slash for
+// non-root paths). The raw href and the placement of the link are recorded
+// alongside the normalized value.
 func Extract(pageURL string, doc *goquery.Document) Info {
 	info := Info{PageURL: pageURL}
 	if doc == nil {
@@ -63,6 +134,10 @@ func Extract(pageURL string, doc *goquery.Document) Info {
 			return true
 		}
 		found = href
+		info.Placement = PlacementHead
+		if s.ParentsFiltered("body").Length() > 0 {
+			info.Placement = PlacementBody
+		}
 		return false
 	})
 
@@ -71,16 +146,122 @@ func Extract(pageURL string, doc *goquery.Document) Info {
 		return info
 	}
 
+	info.RawHref = found
+	if parsed, err := url.Parse(found); err == nil {
+		info.Relative = !parsed.IsAbs()
+	}
+
 	resolved, ok := resolveAgainstPage(pageURL, found)
 	if !ok {
 		info.CanonicalURL = found
+		info.HTMLCanonicalURL = found
 		return info
 	}
 
 	info.CanonicalURL = resolved
+	info.HTMLCanonicalURL = resolved
 	return info
 }
 
+// MergeHeader merges a canonical declared in the HTTP Link header into info.
+// headerHref is the raw link target and may be relative to the page. The
+// HTML canonical keeps precedence; the header value is used when the page has
+// no HTML canonical (e.g. PDFs).
+func MergeHeader(info Info, headerHref string) Info {
+	headerHref = strings.TrimSpace(headerHref)
+	if headerHref == "" {
+		return info
+	}
+
+	resolved, ok := resolveAgainstPage(info.PageURL, headerHref)
+	if !ok {
+		resolved = headerHref
+	}
+	info.HeaderCanonicalURL = resolved
+
+	if info.HTMLCanonicalURL == "" {
+		info.CanonicalURL = resolved
+		info.Missing = false
+	}
+	return info
+}
+
+// InfoIssues returns the findings that can be derived from a single page's
+// canonical extraction details: a Link header that disagrees with the HTML
+// canonical, a canonical placed in <body> (ignored by search engines), and a
+// raw href that is relative, carries a query string or has an upper-case host.
+func InfoIssues(info Info) []Issue {
+	issues := make([]Issue, 0)
+	if info.HTMLCanonicalURL != "" {
+		if info.Placement == PlacementBody {
+			issues = append(issues, Issue{
+				PageURL:      info.PageURL,
+				CanonicalURL: info.HTMLCanonicalURL,
+				Type:         IssueCanonicalInBody,
+				Detail:       "canonical link is in <body> and will be ignored by search engines",
+			})
+		}
+		if info.Relative {
+			issues = append(issues, Issue{
+				PageURL:      info.PageURL,
+				CanonicalURL: info.HTMLCanonicalURL,
+				Type:         IssueRelativeURL,
+				Detail:       "canonical href is relative: " + info.RawHref,
+			})
+		}
+		if parsed, err := url.Parse(info.RawHref); err == nil {
+			if parsed.RawQuery != "" {
+				issues = append(issues, Issue{
+					PageURL:      info.PageURL,
+					CanonicalURL: info.HTMLCanonicalURL,
+					Type:         IssueQueryString,
+					Detail:       "canonical href contains a query string: " + info.RawHref,
+				})
+			}
+			if parsed.Host != strings.ToLower(parsed.Host) {
+				issues = append(issues, Issue{
+					PageURL:      info.PageURL,
+					CanonicalURL: info.HTMLCanonicalURL,
+					Type:         IssueUppercaseHost,
+					Detail:       "canonical href has an upper-case host: " + info.RawHref,
+				})
+			}
+		}
+	}
+	if info.HTMLCanonicalURL != "" && info.HeaderCanonicalURL != "" && info.HTMLCanonicalURL != info.HeaderCanonicalURL {
+		issues = append(issues, Issue{
+			PageURL:      info.PageURL,
+			CanonicalURL: info.HTMLCanonicalURL,
+			Type:         IssueHeaderHTMLMismatch,
+			Detail:       "Link header declares canonical " + info.HeaderCanonicalURL,
+		})
+	}
+	return issues
+}
+
+// HeaderHref returns the raw target of the first rel="canonical" link in the
+// HTTP Link header, or "" when there is none.
+func HeaderHref(header http.Header) string {
+	for _, link := range linkheader.FromHeader(header) {
+		if link.HasRel("canonical") {
+			return link.URL
+		}
+	}
+	return ""
+}
+
+// Normalize applies canonical URL normalization to an absolute URL: lower-case
+// host, no fragment, and no trailing slash on non-root paths.
+func Normalize(raw string) (string, bool) {
+	return normalizeURL(raw)
+}
+
+// Resolve resolves href against pageURL and normalizes the result like
+// Normalize.
+func Resolve(pageURL, href string) (string, bool) {
+	return resolveAgainstPage(pageURL, href)
+}
+
 func resolveAgainstPage(pageURL, href string) (string, bool) {
 	base, err := url.Parse(pageURL)
 	if err != nil {
@@ -100,6 +281,7 @@ func normalizeURL(raw string) (string, bool) {
 		return "", false
 	}
 	parsed.Fragment = ""
+	parsed.Host = strings.ToLower(parsed.Host)
 	if parsed.Path == "" {
 		parsed.Path = "/"
 	}
@@ -133,6 +315,104 @@ func Validate(canonicalByPage map[string]string, statusByURL map[string]int) []I
 		}
 	}
 
+	SortIssues(issues)
+
+	return issues
+}
+
+// ValidateConsistency checks canonical targets against indexability and
+// sitemap signals: targets that are noindex, blocked by robots.txt, excluded
+// from the crawl or canonicalized elsewhere themselves, and sitemap URLs
+// whose canonical points to a different URL. Self-referencing canonicals are
+// not checked against the target rules.
+func ValidateConsistency(canonicalByPage map[string]string, signals Signals) []Issue {
+	issues := make([]Issue, 0)
+
+	for page, target := range canonicalByPage {
+		if target == "" || target == page {
+			continue
+		}
+
+		if _, ok := signals.Noindex[target]; ok {
+			issues = append(issues, Issue{PageURL: page, CanonicalURL: target, Type: IssueTargetNoindex, Detail: "canonical target is marked noindex"})
+		}
+		if signals.RobotsBlocked != nil && signals.RobotsBlocked(target) {
+			issues = append(issues, Issue{PageURL: page, CanonicalURL: target, Type: IssueTargetRobotsBlocked, Detail: "canonical target is disallowed by robots.txt"})
+		}
+		if signals.Excluded != nil && signals.Excluded(target) {
+			issues = append(issues, Issue{PageURL: page, CanonicalURL: target, Type: IssueTargetExcluded, Detail: "canonical target matches an --exclude pattern"})
+		}
+	}
+
+	for _, loc := range signals.SitemapURLs {
+		if target, ok := canonicalByPage[loc]; ok && target != "" && target != loc {
+			issues = append(issues, Issue{PageURL: loc, CanonicalURL: target, Type: IssueSitemapNonCanonical, Detail: "sitemap lists a URL whose canonical points elsewhere"})
+		}
+	}
+
+	SortIssues(issues)
+	return issues
+}
+
+// SelfCanonical returns the URLs from urls whose canonical is either absent
+// or points to the URL itself, preserving order.
+func SelfCanonical(urls []string, canonicalByPage map[string]string) []string {
+	kept := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if target, ok := canonicalByPage[u]; ok && target != "" && target != u {
+			continue
+		}
+		kept = append(kept, u)
+	}
+	return kept
+}
+
+// Clusters groups pages by their canonical target. Only clusters with at
+// least one member other than the target itself are returned, largest first.
+// inlinks maps URLs to the number of pages linking to them and may be nil.
+func Clusters(canonicalByPage map[string]string, statusByURL map[string]int, inlinks map[string]int) []Cluster {
+	membersByTarget := make(map[string][]Member)
+	for page, target := range canonicalByPage {
+		if target == "" {
+			continue
+		}
+		membersByTarget[target] = append(membersByTarget[target], Member{URL: page, Inlinks: inlinks[page]})
+	}
+
+	clusters := make([]Cluster, 0)
+	for target, members := range membersByTarget {
+		if len(members) == 1 && members[0].URL == target {
+			continue
+		}
+
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].Inlinks != members[j].Inlinks {
+				return members[i].Inlinks > members[j].Inlinks
+			}
+			return members[i].URL < members[j].URL
+		})
+
+		status, known := statusByURL[target]
+		clusters = append(clusters, Cluster{
+			CanonicalURL: target,
+			Status:       status,
+			StatusKnown:  known,
+			Members:      members,
+		})
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Members) != len(clusters[j].Members) {
+			return len(clusters[i].Members) > len(clusters[j].Members)
+		}
+		return clusters[i].CanonicalURL < clusters[j].CanonicalURL
+	})
+
+	return clusters
+}
+
+// SortIssues orders issues by page URL, type, canonical URL and detail.
+func SortIssues(issues []Issue) {
 	sort.Slice(issues, func(i, j int) bool {
 		if issues[i].PageURL != issues[j].PageURL {
 			return issues[i].PageURL < issues[j].PageURL
@@ -145,8 +425,6 @@ func Validate(canonicalByPage map[string]string, statusByURL map[string]int) []I
 		}
 		return issues[i].Detail < issues[j].Detail
 	})
-
-	return issues
 }
 
 func validatePair(page, target string, statusByURL map[string]int) (Issue, bool) {
@@ -1,6 +1,7 @@
 package canonical
 
 import (
+	"net/http"
 	"strings"
 	"testing"
 
@@ -88,6 +89,62 @@ func TestExtract_EmptyHrefCanonical(t *testing.T) {
 	}
 }
 
+func TestExtract_RecordsRawHrefAndPlacement(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><link rel="canonical" href="/about/"/></head></html>`)
+
+	info := Extract("https://example.com/page", doc)
+	if info.RawHref != "/about/" || !info.Relative || info.Placement != PlacementHead {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+	if info.CanonicalURL != "https://example.com/about" {
+		t.Fatalf("CanonicalURL=%q", info.CanonicalURL)
+	}
+}
+
+func TestExtract_BodyPlacement(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><title>x</title></head><body><div><link rel="canonical" href="https://example.com/a"/></div></body></html>`)
+
+	info := Extract("https://example.com/page", doc)
+	if info.Placement != PlacementBody {
+		t.Fatalf("Placement=%q, want body", info.Placement)
+	}
+	if info.Relative {
+		t.Fatal("expected absolute href")
+	}
+}
+
+func TestExtract_LowercasesHost(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><link rel="canonical" href="https://Example.COM/About"/></head></html>`)
+
+	info := Extract("https://example.com/page", doc)
+	if info.CanonicalURL != "https://example.com/About" {
+		t.Fatalf("CanonicalURL=%q, want lower-cased host only", info.CanonicalURL)
+	}
+}
+
+func TestInfoIssues_PlacementAndSyntax(t *testing.T) {
+	doc := docFromHTML(t, `<html><body><link rel="canonical" href="//Example.com/list?page=2"/></body></html>`)
+	info := Extract("https://example.com/list", doc)
+
+	found := make(map[IssueType]bool)
+	for _, issue := range InfoIssues(info) {
+		found[issue.Type] = true
+	}
+	for _, want := range []IssueType{IssueCanonicalInBody, IssueRelativeURL, IssueQueryString, IssueUppercaseHost} {
+		if !found[want] {
+			t.Errorf("missing %s issue, got %v", want, found)
+		}
+	}
+}
+
+func TestInfoIssues_CleanCanonical(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><link rel="canonical" href="https://example.com/list"/></head></html>`)
+
+	if issues := InfoIssues(Extract("https://example.com/list", doc)); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", issues)
+	}
+}
+
 func TestValidate_NonHTTPScheme(t *testing.T) {
 	issues := Validate(
 		map[string]string{"https://example.com/page": "mailto:seo@example.com"},
@@ -202,3 +259,187 @@ func TestValidate_SelfCanonicalIsOK(t *testing.T) {
 		t.Fatalf("issues len=%d, want 0", len(issues))
 	}
 }
+
+func TestMergeHeader_UsedWhenHTMLMissing(t *testing.T) {
+	info := Extract("https://example.com/docs/guide.pdf", nil)
+	info = MergeHeader(info, "/docs/guide")
+
+	if info.Missing {
+		t.Fatal("expected Missing=false when header declares a canonical")
+	}
+	if info.CanonicalURL != "https://example.com/docs/guide" {
+		t.Fatalf("CanonicalURL=%q, want header canonical", info.CanonicalURL)
+	}
+	if info.HeaderCanonicalURL != "https://example.com/docs/guide" {
+		t.Fatalf("HeaderCanonicalURL=%q", info.HeaderCanonicalURL)
+	}
+	if issues := InfoIssues(info); len(issues) != 0 {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+}
+
+func TestMergeHeader_HTMLTakesPrecedence(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><link rel="canonical" href="https://example.com/a"/></head></html>`)
+
+	info := MergeHeader(Extract("https://example.com/page", doc), "https://example.com/b/")
+	if info.CanonicalURL != "https://example.com/a" {
+		t.Fatalf("CanonicalURL=%q, want HTML canonical", info.CanonicalURL)
+	}
+
+	issues := InfoIssues(info)
+	if len(issues) != 1 || issues[0].Type != IssueHeaderHTMLMismatch {
+		t.Fatalf("expected header_html_mismatch issue, got %+v", issues)
+	}
+	if !strings.Contains(issues[0].Detail, "https://example.com/b") {
+		t.Fatalf("Detail=%q should mention header canonical", issues[0].Detail)
+	}
+}
+
+func TestMergeHeader_AgreeingValues(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><link rel="canonical" href="/a"/></head></html>`)
+
+	info := MergeHeader(Extract("https://example.com/page", doc), "https://example.com/a/")
+	for _, issue := range InfoIssues(info) {
+		if issue.Type == IssueHeaderHTMLMismatch {
+			t.Fatalf("normalized equal canonicals should not conflict: %+v", issue)
+		}
+	}
+}
+
+func TestMergeHeader_EmptyHeader(t *testing.T) {
+	info := Extract("https://example.com/page", nil)
+	if merged := MergeHeader(info, "  "); merged != info {
+		t.Fatalf("empty header should leave info unchanged: %+v", merged)
+	}
+}
+
+func TestValidateConsistency(t *testing.T) {
+	canonicalByPage := map[string]string{
+		"https://example.com/a":      "https://example.com/noindex",
+		"https://example.com/b":      "https://example.com/blocked",
+		"https://example.com/c":      "https://example.com/print/c",
+		"https://example.com/d":      "https://example.com/e",
+		"https://example.com/e":      "https://example.com/f",
+		"https://example.com/self":   "https://example.com/self",
+		"https://example.com/listed": "https://example.com/self",
+	}
+
+	issues := ValidateConsistency(canonicalByPage, Signals{
+		Noindex: map[string]struct{}{"https://example.com/noindex": {}},
+		RobotsBlocked: func(u string) bool {
+			return u == "https://example.com/blocked"
+		},
+		Excluded: func(u string) bool {
+			return strings.Contains(u, "/print/")
+		},
+		SitemapURLs: []string{"https://example.com/self", "https://example.com/listed"},
+	})
+
+	want := map[IssueType]string{
+		IssueTargetNoindex:       "https://example.com/a",
+		IssueTargetRobotsBlocked: "https://example.com/b",
+		IssueTargetExcluded:      "https://example.com/c",
+		IssueSitemapNonCanonical: "https://example.com/listed",
+	}
+	for issueType, page := range want {
+		found := false
+		for _, issue := range issues {
+			if issue.Type == issueType && issue.PageURL == page {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing %s issue for %s", issueType, page)
+		}
+	}
+
+	for _, issue := range issues {
+		if issue.PageURL == "https://example.com/self" {
+			t.Errorf("self-canonical page should have no issues: %+v", issue)
+		}
+		if issue.PageURL == "https://example.com/d" {
+			t.Errorf("canonical chains are reported by Validate, not ValidateConsistency: %+v", issue)
+		}
+	}
+}
+
+func TestValidateConsistency_NilSignals(t *testing.T) {
+	issues := ValidateConsistency(map[string]string{"https://example.com/a": "https://example.com/b"}, Signals{})
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues without signals, got %+v", issues)
+	}
+}
+
+func TestSelfCanonical(t *testing.T) {
+	got := SelfCanonical(
+		[]string{"https://example.com/a", "https://example.com/b", "https://example.com/c"},
+		map[string]string{
+			"https://example.com/a": "https://example.com/a",
+			"https://example.com/b": "https://example.com/a",
+		},
+	)
+
+	if strings.Join(got, ",") != "https://example.com/a,https://example.com/c" {
+		t.Fatalf("SelfCanonical=%v", got)
+	}
+}
+
+func TestClusters(t *testing.T) {
+	canonicalByPage := map[string]string{
+		"https://example.com/list":       "https://example.com/list",
+		"https://example.com/list?p=2":   "https://example.com/list",
+		"https://example.com/list?p=3":   "https://example.com/list",
+		"https://example.com/list?s=asc": "https://example.com/list",
+		"https://example.com/self":       "https://example.com/self",
+		"https://example.com/old":        "https://example.com/gone",
+	}
+	statusByURL := map[string]int{
+		"https://example.com/list": 200,
+	}
+	inlinks := map[string]int{
+		"https://example.com/list":       10,
+		"https://example.com/list?p=2":   4,
+		"https://example.com/list?s=asc": 4,
+	}
+
+	clusters := Clusters(canonicalByPage, statusByURL, inlinks)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", clusters)
+	}
+
+	list := clusters[0]
+	if list.CanonicalURL != "https://example.com/list" || !list.StatusKnown || list.Status != 200 {
+		t.Fatalf("unexpected first cluster: %+v", list)
+	}
+	gotOrder := make([]string, 0, len(list.Members))
+	for _, m := range list.Members {
+		gotOrder = append(gotOrder, m.URL)
+	}
+	wantOrder := "https://example.com/list,https://example.com/list?p=2,https://example.com/list?s=asc,https://example.com/list?p=3"
+	if strings.Join(gotOrder, ",") != wantOrder {
+		t.Errorf("member order=%v", gotOrder)
+	}
+
+	gone := clusters[1]
+	if gone.CanonicalURL != "https://example.com/gone" || gone.StatusKnown || len(gone.Members) != 1 {
+		t.Errorf("unexpected second cluster: %+v", gone)
+	}
+}
+
+func TestHeaderHref(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `<https://example.com/a.css>; rel="preload", <https://example.com/page>; rel="canonical"`)
+	if got := HeaderHref(header); got != "https://example.com/page" {
+		t.Errorf("HeaderHref=%q, want https://example.com/page", got)
+	}
+	if got := HeaderHref(http.Header{}); got != "" {
+		t.Errorf("HeaderHref on empty header=%q, want empty", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got, ok := Normalize("https://EXAMPLE.com/blog/#top")
+	if !ok || got != "https://example.com/blog" {
+		t.Errorf("Normalize=%q,%v, want https://example.com/blog", got, ok)
+	}
+}
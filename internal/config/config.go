@@ -0,0 +1,66 @@
+// Package config loads the optional GopherSEO project configuration file, a
+// JSON document such as:
+//
+//	{
+//	  "sitemap": {
+//	    "rules": [
+//	      {"max_depth": 0, "priority": 1.0, "changefreq": "daily"},
+//	      {"pattern": "/blog/*", "changefreq": "auto", "priority": 0.6},
+//	      {"min_depth": 3, "priority": 0.3}
+//	    ]
+//	  }
+//	}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/tariktz/gopherseo/internal/sitemap"
+)
+
+// DefaultPath is the project config file read when --config is not given.
+const DefaultPath = ".gopherseo.json"
+
+// Config is the project configuration.
+type Config struct {
+	Sitemap Sitemap `json:"sitemap"`
+}
+
+// Sitemap configures generated sitemap content.
+type Sitemap struct {
+	// Rules assign <changefreq> and <priority>; the first matching rule that
+	// sets an attribute wins.
+	Rules []sitemap.Rule `json:"rules"`
+}
+
+// Load reads and validates the config file at path. When optional is true a
+// missing file yields an empty Config instead of an error.
+func Load(path string, optional bool) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if optional && errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("read config: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	for i, rule := range cfg.Sitemap.Rules {
+		if err := rule.Validate(); err != nil {
+			return cfg, fmt.Errorf("config %s: sitemap rule %d: %w", path, i+1, err)
+		}
+	}
+	return cfg, nil
+}
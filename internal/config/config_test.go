@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `{"sitemap": {"rules": [
+		{"max_depth": 0, "priority": 1.0, "changefreq": "daily"},
+		{"pattern": "/blog/*", "changefreq": "auto"}
+	]}}`)
+
+	cfg, err := Load(path, false)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	rules := cfg.Sitemap.Rules
+	if len(rules) != 2 || *rules[0].MaxDepth != 0 || *rules[0].Priority != 1.0 || rules[1].Pattern != "/blog/*" {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	if _, err := Load(missing, true); err != nil {
+		t.Errorf("optional missing config should not fail: %v", err)
+	}
+	if _, err := Load(missing, false); err == nil {
+		t.Error("required missing config should fail")
+	}
+}
+
+func TestLoad_InvalidRule(t *testing.T) {
+	path := writeConfig(t, `{"sitemap": {"rules": [{"pattern": "*", "changefreq": "sometimes"}]}}`)
+
+	_, err := Load(path, false)
+	if err == nil || !strings.Contains(err.Error(), "sitemap rule 1") {
+		t.Fatalf("expected rule validation error, got %v", err)
+	}
+}
+
+func TestLoad_UnknownField(t *testing.T) {
+	path := writeConfig(t, `{"sitemap": {"rulez": []}}`)
+
+	if _, err := Load(path, false); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
@@ -4,23 +4,36 @@ package crawler
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
-	pathpkg "path"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
+	"github.com/tariktz/gopherseo/internal/auth"
 	"github.com/tariktz/gopherseo/internal/canonical"
+	"github.com/tariktz/gopherseo/internal/hreflang"
+	"github.com/tariktz/gopherseo/internal/images"
 	"github.com/tariktz/gopherseo/internal/lastmod"
+	"github.com/tariktz/gopherseo/internal/media"
+	"github.com/tariktz/gopherseo/internal/robots"
+	"github.com/tariktz/gopherseo/internal/soft404"
+	"github.com/tariktz/gopherseo/internal/throttle"
+	"github.com/tariktz/gopherseo/internal/urlglob"
 )
 
 const defaultUserAgent = "GopherSEO-Bot/1.0"
 
+// DefaultRobotsInlinkThreshold is the number of linking pages from which a
+// robots-blocked URL is flagged.
+const DefaultRobotsInlinkThreshold = 5
+
 // Options configures the behaviour of a crawl run.
 type Options struct {
 	// RootURL is the seed URL from which crawling starts.
@@ -38,20 +51,102 @@ type Options struct {
 	// RequestTimeout is the maximum duration for a single HTTP request.
 	// A zero value means no timeout.
 	RequestTimeout time.Duration
+	// DetectSoft404 enables soft-404 detection: the site's error page is
+	// fingerprinted by probing a random path under the root, and 200
+	// responses that match it (or resemble it and have a "not found"
+	// title/h1) are reported as broken instead of valid.
+	DetectSoft404 bool
+	// AuditImages fetches every image embedded on valid pages (honouring
+	// Threads and RequestTimeout) and populates Result.ImageAudits.
+	AuditImages bool
+	// ImageMaxBytes is the byte budget above which an image is flagged as
+	// oversize. A value of 0 disables the check.
+	ImageMaxBytes int64
+	// CanonicalOnlySitemap drops pages whose canonical points to a different
+	// URL from Result.SitemapURLs.
+	CanonicalOnlySitemap bool
+	// LastmodStorePath points to a JSON content-hash store. When set, pages
+	// without a declared modification date keep the lastmod recorded by the
+	// previous crawl as long as their main content hash is unchanged, and the
+	// store is updated after the crawl.
+	LastmodStorePath string
+	// LastmodConflictThreshold is the spread between a page's candidate
+	// lastmod dates above which they are reported as conflicting. Zero uses
+	// lastmod.DefaultConflictThreshold.
+	LastmodConflictThreshold time.Duration
+	// LastmodSources enables lastmod sources in priority order. Empty uses
+	// lastmod.DefaultSources.
+	LastmodSources []lastmod.Source
+	// SitemapLastmod maps URLs to the <lastmod> of an existing sitemap, used
+	// by lastmod.SourceSitemap. Keys are normalized like crawled URLs.
+	SitemapLastmod map[string]time.Time
+	// Location is the time zone assumed for lastmod values without an
+	// offset. Nil means UTC.
+	Location *time.Location
+	// RobotsInlinkThreshold is the number of linking pages from which a
+	// robots-blocked URL is flagged. Zero uses DefaultRobotsInlinkThreshold.
+	RobotsInlinkThreshold int
+	// Delay is the minimum time between two requests to the root host. A
+	// longer robots.txt Crawl-delay takes precedence unless IgnoreCrawlDelay
+	// is set.
+	Delay time.Duration
+	// Jitter adds a random extra delay of up to Jitter to every request.
+	Jitter time.Duration
+	// IgnoreCrawlDelay disregards the robots.txt Crawl-delay.
+	IgnoreCrawlDelay bool
+	// AdaptiveConcurrency lowers the number of parallel requests below
+	// Threads while response times are rising and after 429/503 responses.
+	AdaptiveConcurrency bool
+	// RateLimitRetries is how often a request answered with 429 or 503 is
+	// retried after pausing for its Retry-After value. Zero uses
+	// throttle.DefaultMaxRetries; a negative value disables retries.
+	RateLimitRetries int
+	// MaxBodySize, when positive, is the largest response body in bytes that
+	// is downloaded; responses announcing a larger Content-Length are
+	// reported as broken with ErrorBodyTooLarge. Zero disables the check and
+	// colly truncates bodies at its 10 MiB default without reporting them.
+	MaxBodySize int
+	// Retry decides which failed requests are retried before their URL is
+	// reported as broken. The zero value disables retries; see
+	// DefaultRetryPolicy.
+	Retry RetryPolicy
+	// Auth holds credentials sent to the root host: basic or bearer
+	// authentication, custom headers, imported cookies and a form login.
+	Auth auth.Options
+	// Resolve sends connections for the listed host and port pairs to fixed
+	// addresses, e.g. to crawl a staging server under the production host
+	// name before DNS cutover.
+	Resolve []Resolve
+	// TLS configures extra root CAs, a client certificate and insecure mode.
+	TLS TLSOptions
+	// Proxies are http://, https:// or socks5:// proxy URLs used in rotation,
+	// one per request, except for hosts listed in NO_PROXY. Without them the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables apply.
+	Proxies []string
+	// OnThrottle, when set, receives every throttling decision (Crawl-delay,
+	// backoff pauses and concurrency changes) for progress output.
+	OnThrottle func(throttle.Event)
 }
 
 // Result holds the output of a completed crawl.
 type Result struct {
 	// ValidURLs contains every discovered URL that returned a 2xx/3xx status.
 	ValidURLs []string
+	// SitemapURLs contains the URLs to write to the sitemap: ValidURLs, minus
+	// non-canonical pages when Options.CanonicalOnlySitemap is set.
+	SitemapURLs []string
 	// BrokenLinks maps each broken URL to its HTTP status code (0 = request failed).
 	BrokenLinks map[string]int
 	// BrokenLinkTasks provides a structured list of broken links together with
 	// the pages on which each broken link was found.
 	BrokenLinkTasks []BrokenLinkTask
 	// LastModified maps each valid URL to its best-available last-modified
-	// timestamp, extracted using the lastmod extraction hierarchy.
-	LastModified map[string]time.Time
+	// timestamp, extracted using the lastmod extraction hierarchy, together
+	// with its source and every candidate date found.
+	LastModified map[string]lastmod.Info
+	// LastmodReport summarizes lastmod provenance: source conflicts, future
+	// dates and how many pages rely on the crawl-time fallback.
+	LastmodReport lastmod.Report
 	// CanonicalByPage maps each crawled page URL to the extracted canonical URL
 	// (when present and resolvable).
 	CanonicalByPage map[string]string
@@ -60,8 +155,54 @@ type Result struct {
 	// MultipleCanonicalPages lists pages where multiple canonical tags were found.
 	MultipleCanonicalPages []string
 	// CanonicalIssues contains canonical validation findings (cross-domain,
-	// non-HTTP, broken/redirect targets, and loop/chain patterns).
+	// non-HTTP, broken/redirect targets, loop/chain patterns, Link header
+	// canonicals that disagree with the HTML, and targets that are noindex,
+	// robots-blocked, excluded or canonicalized elsewhere).
 	CanonicalIssues []canonical.Issue
+	// CanonicalClusters groups crawled pages by canonical target, largest
+	// cluster first.
+	CanonicalClusters []canonical.Cluster
+	// InlinkCounts maps each discovered URL to the number of distinct crawled
+	// pages linking to it.
+	InlinkCounts map[string]int
+	// NoindexPages lists valid pages that declared noindex via meta robots or
+	// the X-Robots-Tag header.
+	NoindexPages []string
+	// Soft404Pages lists pages that returned 200 but were detected as error
+	// pages. They are also reported in BrokenLinks and BrokenLinkTasks.
+	Soft404Pages []string
+	// ImagesByPage maps each valid page URL to the <img> elements it embeds.
+	ImagesByPage map[string][]images.Image
+	// ImageAudits holds one entry per unique image when Options.AuditImages
+	// is set.
+	ImageAudits []images.Audit
+	// VideosByPage maps each valid page URL to the videos it declares via
+	// <video> elements or VideoObject JSON-LD.
+	VideosByPage map[string][]media.Video
+	// NewsByPage maps each valid page URL to its NewsArticle JSON-LD.
+	NewsByPage map[string]media.NewsArticle
+	// MediaIssues lists videos and news articles missing fields required by
+	// the video and news sitemap extensions.
+	MediaIssues []media.Issue
+	// HreflangByPage maps each valid page URL to the hreflang alternates it
+	// declares in HTML or the Link header.
+	HreflangByPage map[string][]hreflang.Alternate
+	// HreflangIssues contains hreflang validation findings (invalid codes,
+	// missing return/self/x-default tags, and broken, redirecting or
+	// non-canonical alternates).
+	HreflangIssues []hreflang.Issue
+	// DepthByURL maps each valid URL to its crawl depth: the number of link
+	// hops from the root (root = 0).
+	DepthByURL map[string]int
+	// RobotsTxt is the parsed robots.txt of the root host, nil when it is
+	// missing or could not be fetched.
+	RobotsTxt *robots.File
+	// RobotsBlocked lists internal links the crawler skipped because
+	// robots.txt disallows them, sorted by URL.
+	RobotsBlocked []RobotsBlockedLink
+	// Certificates lists the TLS certificate of every HTTPS host requested
+	// during the crawl, sorted by host.
+	Certificates []Certificate
 	// Discovered is the total number of unique URLs seen during the crawl.
 	Discovered int
 	// ExcludedURLs is the number of URLs that were skipped due to exclusion rules.
@@ -74,6 +215,36 @@ type BrokenLinkTask struct {
 	URL     string
 	Status  int
 	Sources []string
+	// Soft404Reason is set when the URL responded with 200 but was detected
+	// as a soft 404; it explains which signal matched.
+	Soft404Reason string
+	// Attempts is the number of requests made for the URL, including
+	// retries.
+	Attempts int
+	// Error is the error of the final attempt, e.g. a timeout message or the
+	// status text.
+	Error string
+	// ErrorClass classifies the failure when the URL produced no usable
+	// HTTP response (Status 0) or an unfinished redirect chain (3xx). It is
+	// empty for HTTP error statuses.
+	ErrorClass ErrorClass
+}
+
+// RobotsBlockedLink is an internal link that robots.txt kept the crawler
+// from fetching, together with every page that links to it.
+type RobotsBlockedLink struct {
+	URL     string
+	Sources []string
+	// CanonicalTarget is set when a crawled page declares URL as its
+	// canonical.
+	CanonicalTarget bool
+	// Rule is the robots.txt rule that blocked URL, e.g.
+	// "line 3: Disallow: /private".
+	Rule string
+	// Flagged is set for canonical targets and URLs linked from at least
+	// Options.RobotsInlinkThreshold pages: blocks that likely hide pages
+	// meant to be indexed.
+	Flagged bool
 }
 
 // Crawl performs a recursive crawl starting from opts.RootURL. It returns a
@@ -102,7 +273,10 @@ func Crawl(opts Options) (Result, error) {
 	}
 
 	c := colly.NewCollector(collectorOptions...)
-	c.IgnoreRobotsTxt = false
+	// robots.txt is fetched once through the throttled client and evaluated
+	// with internal/robots, so blocked links, the robots report and
+	// "gopherseo robots test" agree.
+	c.IgnoreRobotsTxt = true
 
 	if err := c.Limit(&colly.LimitRule{DomainGlob: "*", Parallelism: opts.Threads}); err != nil {
 		return Result{}, fmt.Errorf("configure crawler concurrency: %w", err)
@@ -111,20 +285,177 @@ func Crawl(opts Options) (Result, error) {
 	if opts.RequestTimeout > 0 {
 		c.SetRequestTimeout(opts.RequestTimeout)
 	}
+	if opts.MaxBodySize > 0 {
+		c.MaxBodySize = opts.MaxBodySize
+	}
+
+	var lastmodStore *lastmod.Store
+	if opts.LastmodStorePath != "" {
+		lastmodStore, err = lastmod.LoadStore(opts.LastmodStorePath)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	lastmodOpts := lastmod.Options{Sources: opts.LastmodSources, Location: opts.Location}
+	if len(opts.SitemapLastmod) > 0 {
+		lastmodOpts.SitemapLastmod = make(map[string]time.Time, len(opts.SitemapLastmod))
+		for loc, t := range opts.SitemapLastmod {
+			if normalized, _, err := normalizeURL(loc); err == nil {
+				lastmodOpts.SitemapLastmod[normalized] = t
+			}
+		}
+	}
+
+	transport, jar, certs, err := newTransport(parsedRoot, opts)
+	if err != nil {
+		return Result{}, err
+	}
+	c.WithTransport(transport)
+	c.SetCookieJar(jar)
+
+	throttler := throttle.New(throttle.Options{
+		Delay:       opts.Delay,
+		Jitter:      opts.Jitter,
+		Concurrency: opts.Threads,
+		Adaptive:    opts.AdaptiveConcurrency,
+		MaxRetries:  opts.RateLimitRetries,
+		OnEvent:     opts.OnThrottle,
+	})
+
+	// httpClient serves the crawler's own requests. The collector's requests
+	// are throttled in OnRequest instead, so they use the bare transport.
+	httpClient := &http.Client{
+		Timeout:   opts.RequestTimeout,
+		Transport: &throttle.Transport{Base: transport, Throttle: throttler},
+		Jar:       jar,
+	}
+
+	if opts.Auth.Login != nil {
+		if err := auth.Login(httpClient, parsedRoot, opts.UserAgent, *opts.Auth.Login); err != nil {
+			return Result{}, err
+		}
+	}
+
+	robotsFile, err := fetchRobots(httpClient, parsedRoot, opts.UserAgent)
+	if err != nil {
+		return Result{}, fmt.Errorf("start crawling: %w", err)
+	}
+
+	if crawlDelay := robotsFile.CrawlDelay(opts.UserAgent); crawlDelay > opts.Delay && !opts.IgnoreCrawlDelay {
+		throttler.SetDelay(crawlDelay)
+		if opts.OnThrottle != nil {
+			opts.OnThrottle(throttle.Event{Type: throttle.EventDelay, Host: parsedRoot.Host, Wait: crawlDelay, Concurrency: opts.Threads,
+				Detail: fmt.Sprintf("robots.txt Crawl-delay: waiting %s between requests to %s", crawlDelay, parsedRoot.Host)})
+		}
+	}
+
+	var fingerprint *soft404.Fingerprint
+	if opts.DetectSoft404 {
+		fingerprint = probeSoft404(httpClient, parsedRoot, opts.UserAgent)
+	}
 
 	var mu sync.Mutex
 	valid := make(map[string]struct{})
 	broken := make(map[string]int)
 	discovered := make(map[string]struct{})
 	sources := make(map[string]map[string]struct{})
-	lastModified := make(map[string]time.Time)
+	lastModified := make(map[string]lastmod.Info)
 	canonicalByPage := make(map[string]string)
 	statusByURL := make(map[string]int)
 	missingCanonicalSet := make(map[string]struct{})
 	multipleCanonicalSet := make(map[string]struct{})
+	soft404Reasons := make(map[string]string)
+	imagesByPage := make(map[string][]images.Image)
+	videosByPage := make(map[string][]media.Video)
+	newsByPage := make(map[string]media.NewsArticle)
+	hreflangByPage := make(map[string][]hreflang.Alternate)
+	depthByURL := make(map[string]int)
+	canonicalInfoIssues := make([]canonical.Issue, 0)
+	noindexSet := make(map[string]struct{})
+	// robotsBlockedRules maps each robots-blocked link to the rule that
+	// blocked it.
+	robotsBlockedRules := make(map[string]string)
+	requestStarts := make(map[uint32]time.Time)
+	// attempts, rateLimitRetries, policyRetries and retryAt are keyed by the
+	// normalized URL a request ended at, which is the URL Request.Retry
+	// requests again after a redirect.
+	attempts := make(map[string]int)
+	rateLimitRetries := make(map[string]int)
+	policyRetries := make(map[string]int)
+	// retryAt holds the earliest start of a scheduled retry. The retried
+	// request waits for it in the throttler instead of the failed request
+	// blocking its callback.
+	retryAt := make(map[string]time.Time)
+	lastErrors := make(map[string]string)
+	errorClasses := make(map[string]ErrorClass)
+	oversized := make(map[uint32]int64)
 	excluded := 0
 	now := time.Now()
 
+	c.OnRequest(func(r *colly.Request) {
+		link, _, err := normalizeURL(r.URL.String())
+		if err != nil {
+			link = r.URL.String()
+		}
+		mu.Lock()
+		notBefore := retryAt[link]
+		delete(retryAt, link)
+		mu.Unlock()
+		throttler.AcquireAfter(r.URL.Host, notBefore)
+
+		mu.Lock()
+		requestStarts[r.ID] = time.Now()
+		mu.Unlock()
+	})
+
+	// releaseRequest ends the throttled request behind r and reports whether
+	// it was rate limited and has been scheduled for a retry.
+	releaseRequest := func(r *colly.Response) bool {
+		link, _, err := normalizeURL(r.Request.URL.String())
+		if err != nil {
+			link = r.Request.URL.String()
+		}
+		mu.Lock()
+		started, ok := requestStarts[r.Request.ID]
+		delete(requestStarts, r.Request.ID)
+		if ok {
+			attempts[link]++
+		}
+		attempt := rateLimitRetries[link]
+		mu.Unlock()
+		if !ok {
+			return false
+		}
+
+		var header http.Header
+		if r.Headers != nil {
+			header = *r.Headers
+		}
+		if !throttler.Release(r.Request.URL.Host, time.Since(started), r.StatusCode, header, attempt) {
+			return false
+		}
+
+		mu.Lock()
+		rateLimitRetries[link]++
+		mu.Unlock()
+		return r.Request.Retry() == nil
+	}
+
+	c.OnResponseHeaders(func(r *colly.Response) {
+		if opts.MaxBodySize <= 0 || r.Headers == nil {
+			return
+		}
+		length, err := strconv.ParseInt(r.Headers.Get("Content-Length"), 10, 64)
+		if err != nil || length <= int64(c.MaxBodySize) {
+			return
+		}
+		mu.Lock()
+		oversized[r.Request.ID] = length
+		mu.Unlock()
+		r.Request.Abort()
+	})
+
 	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
 		raw := strings.TrimSpace(e.Attr("href"))
 		if raw == "" {
@@ -161,12 +492,20 @@ func Crawl(opts Options) (Result, error) {
 			}
 			sources[normalizedLink][sourceURL] = struct{}{}
 		}
+		match := robotsFile.Match(opts.UserAgent, normalizedLink)
+		if !match.Allowed {
+			robotsBlockedRules[normalizedLink] = robotsRule(match)
+		}
 		mu.Unlock()
 
-		_ = e.Request.Visit(normalizedLink)
+		if match.Allowed {
+			_ = e.Request.Visit(normalizedLink)
+		}
 	})
 
 	c.OnResponse(func(r *colly.Response) {
+		releaseRequest(r)
+
 		normalizedLink, _, err := normalizeURL(r.Request.URL.String())
 		if err != nil {
 			return
@@ -179,18 +518,64 @@ func Crawl(opts Options) (Result, error) {
 
 		doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(r.Body))
 		canonicalInfo := canonical.Extract(normalizedLink, doc)
-		extractedLastMod := lastmod.GetLastModified(header, doc, now)
+		canonicalInfo = canonical.MergeHeader(canonicalInfo, canonical.HeaderHref(header))
+		lastmodInfo := lastmod.ExtractWithOptions(normalizedLink, header, doc, now, lastmodOpts)
+		contentHash := ""
+		if lastmodInfo.Source == lastmod.SourceFallback && lastmodStore != nil {
+			contentHash = lastmod.ContentHash(doc)
+		}
+		pageImages := images.Extract(normalizedLink, doc)
+		pageVideos := media.ExtractVideos(normalizedLink, doc)
+		pageNews, hasNews := media.ExtractNews(normalizedLink, doc)
+		pageAlternates := hreflang.Extract(normalizedLink, header, doc)
+		noindex := robots.IsNoindex(header, doc)
+
+		soft404Reason := ""
+		if opts.DetectSoft404 && r.StatusCode == http.StatusOK && isHTML(header) {
+			if reason, ok := soft404.Detect(normalizedLink, doc, fingerprint); ok {
+				soft404Reason = reason
+			}
+		}
 
 		mu.Lock()
 		defer mu.Unlock()
 
 		discovered[normalizedLink] = struct{}{}
 		statusByURL[normalizedLink] = r.StatusCode
+		if soft404Reason != "" {
+			broken[normalizedLink] = r.StatusCode
+			soft404Reasons[normalizedLink] = soft404Reason
+			delete(valid, normalizedLink)
+			return
+		}
 		if r.StatusCode >= 200 && r.StatusCode < 400 {
 			valid[normalizedLink] = struct{}{}
 			delete(broken, normalizedLink)
 
-			lastModified[normalizedLink] = extractedLastMod
+			if contentHash != "" && r.StatusCode < 300 {
+				lastmodInfo.Time = lastmodStore.Resolve(normalizedLink, contentHash, now)
+				if !lastmodInfo.Time.Equal(now.UTC()) {
+					lastmodInfo.Source = lastmod.SourceHistory
+				}
+				lastmodInfo.ChangeInterval, _ = lastmodStore.ChangeInterval(normalizedLink)
+			}
+			lastModified[normalizedLink] = lastmodInfo
+			depth := r.Request.Depth - 1
+			if prev, ok := depthByURL[normalizedLink]; !ok || depth < prev {
+				depthByURL[normalizedLink] = depth
+			}
+			if len(pageImages) > 0 {
+				imagesByPage[normalizedLink] = pageImages
+			}
+			if len(pageVideos) > 0 {
+				videosByPage[normalizedLink] = pageVideos
+			}
+			if hasNews {
+				newsByPage[normalizedLink] = pageNews
+			}
+			if len(pageAlternates) > 0 {
+				hreflangByPage[normalizedLink] = pageAlternates
+			}
 			if canonicalInfo.CanonicalURL != "" {
 				canonicalByPage[normalizedLink] = canonicalInfo.CanonicalURL
 			}
@@ -200,6 +585,10 @@ func Crawl(opts Options) (Result, error) {
 			if canonicalInfo.Multiple {
 				multipleCanonicalSet[normalizedLink] = struct{}{}
 			}
+			canonicalInfoIssues = append(canonicalInfoIssues, canonical.InfoIssues(canonicalInfo)...)
+			if noindex {
+				noindexSet[normalizedLink] = struct{}{}
+			}
 			return
 		}
 
@@ -211,6 +600,9 @@ func Crawl(opts Options) (Result, error) {
 		if r == nil || r.Request == nil || r.Request.URL == nil {
 			return
 		}
+		if releaseRequest(r) {
+			return
+		}
 
 		normalizedLink, _, parseErr := normalizeURL(r.Request.URL.String())
 		if parseErr != nil {
@@ -220,17 +612,51 @@ func Crawl(opts Options) (Result, error) {
 		status := r.StatusCode
 
 		mu.Lock()
+		if length, ok := oversized[r.Request.ID]; ok && errors.Is(err, colly.ErrAbortedAfterHeaders) {
+			err = fmt.Errorf("%w: Content-Length %d exceeds %d bytes", ErrBodyTooLarge, length, c.MaxBodySize)
+		}
+		delete(oversized, r.Request.ID)
+		retries := policyRetries[normalizedLink]
+		retry := opts.Retry.retryable(status, err, retries+1)
+		if retry {
+			policyRetries[normalizedLink]++
+			retryAt[normalizedLink] = time.Now().Add(opts.Retry.backoff(retries + 1))
+		}
+		mu.Unlock()
+		if retry {
+			if r.Request.Retry() == nil {
+				return
+			}
+			mu.Lock()
+			delete(retryAt, normalizedLink)
+			mu.Unlock()
+		}
+
+		mu.Lock()
+		if err != nil {
+			lastErrors[normalizedLink] = err.Error()
+		}
+		errorClasses[normalizedLink] = classifyFailure(status, err)
 		broken[normalizedLink] = status
 		statusByURL[normalizedLink] = status
 		delete(valid, normalizedLink)
 		mu.Unlock()
 	})
 
+	if !robotsFile.Allowed(opts.UserAgent, normalizedRoot) {
+		return Result{}, fmt.Errorf("start crawling: %w", colly.ErrRobotsTxtBlocked)
+	}
 	if err := c.Visit(normalizedRoot); err != nil {
 		return Result{}, fmt.Errorf("start crawling: %w", err)
 	}
 	c.Wait()
 
+	if lastmodStore != nil {
+		if err := lastmodStore.Save(); err != nil {
+			return Result{}, err
+		}
+	}
+
 	validURLs := make([]string, 0, len(valid))
 	for u := range valid {
 		if shouldExclude(u, opts.ExcludePatterns) {
@@ -258,9 +684,13 @@ func Crawl(opts Options) (Result, error) {
 		}
 
 		brokenTasks = append(brokenTasks, BrokenLinkTask{
-			URL:     u,
-			Status:  status,
-			Sources: sourceList,
+			URL:           u,
+			Status:        status,
+			Sources:       sourceList,
+			Soft404Reason: soft404Reasons[u],
+			Attempts:      attempts[u],
+			Error:         lastErrors[u],
+			ErrorClass:    errorClasses[u],
 		})
 	}
 	sort.Slice(brokenTasks, func(i, j int) bool {
@@ -279,22 +709,139 @@ func Crawl(opts Options) (Result, error) {
 	}
 	sort.Strings(multipleCanonicalPages)
 
-	canonicalIssues := canonical.Validate(canonicalByPage, statusByURL)
+	soft404Pages := make([]string, 0, len(soft404Reasons))
+	for page := range soft404Reasons {
+		if shouldExclude(page, opts.ExcludePatterns) {
+			continue
+		}
+		soft404Pages = append(soft404Pages, page)
+	}
+	sort.Strings(soft404Pages)
+
+	sitemapURLs := validURLs
+	if opts.CanonicalOnlySitemap {
+		sitemapURLs = canonical.SelfCanonical(validURLs, canonicalByPage)
+	}
+
+	noindexPages := make([]string, 0, len(noindexSet))
+	for page := range noindexSet {
+		noindexPages = append(noindexPages, page)
+	}
+	sort.Strings(noindexPages)
+
+	canonicalIssues := append(canonical.Validate(canonicalByPage, statusByURL), canonicalInfoIssues...)
+	canonicalIssues = append(canonicalIssues, canonical.ValidateConsistency(canonicalByPage, canonical.Signals{
+		Noindex: noindexSet,
+		RobotsBlocked: func(u string) bool {
+			// robotsFile only applies to the root host; targets elsewhere
+			// are governed by their own robots.txt.
+			target, err := url.Parse(u)
+			if err != nil || !strings.EqualFold(target.Scheme, parsedRoot.Scheme) || !strings.EqualFold(target.Host, parsedRoot.Host) {
+				return false
+			}
+			return !robotsFile.Allowed(opts.UserAgent, u)
+		},
+		Excluded: func(u string) bool {
+			return shouldExclude(u, opts.ExcludePatterns)
+		},
+		SitemapURLs: sitemapURLs,
+	})...)
+	canonical.SortIssues(canonicalIssues)
+
+	inlinkCounts := make(map[string]int, len(sources))
+	for u, sourceSet := range sources {
+		inlinkCounts[u] = len(sourceSet)
+	}
+	canonicalClusters := canonical.Clusters(canonicalByPage, statusByURL, inlinkCounts)
+	robotsBlocked := buildRobotsBlocked(robotsBlockedRules, sources, canonicalByPage, opts.RobotsInlinkThreshold)
+
+	mediaIssues := media.Validate(videosByPage, newsByPage)
+	hreflangIssues := hreflang.Validate(hreflangByPage, statusByURL, canonicalByPage)
+
+	var imageAudits []images.Audit
+	if opts.AuditImages {
+		imageMeta := fetchImageMetadata(httpClient, opts.UserAgent, imagesByPage, opts.Threads)
+		imageAudits = images.BuildAudits(imagesByPage, imageMeta, opts.ImageMaxBytes)
+	}
 
 	return Result{
 		ValidURLs:              validURLs,
+		SitemapURLs:            sitemapURLs,
 		BrokenLinks:            brokenURLs,
 		BrokenLinkTasks:        brokenTasks,
 		LastModified:           lastModified,
+		LastmodReport:          lastmod.Analyze(lastModified, now, opts.LastmodConflictThreshold),
 		CanonicalByPage:        canonicalByPage,
 		MissingCanonicalPages:  missingCanonicalPages,
 		MultipleCanonicalPages: multipleCanonicalPages,
 		CanonicalIssues:        canonicalIssues,
+		CanonicalClusters:      canonicalClusters,
+		InlinkCounts:           inlinkCounts,
+		NoindexPages:           noindexPages,
+		Soft404Pages:           soft404Pages,
+		ImagesByPage:           imagesByPage,
+		ImageAudits:            imageAudits,
+		VideosByPage:           videosByPage,
+		NewsByPage:             newsByPage,
+		MediaIssues:            mediaIssues,
+		HreflangByPage:         hreflangByPage,
+		HreflangIssues:         hreflangIssues,
+		DepthByURL:             depthByURL,
+		RobotsTxt:              robotsFile,
+		RobotsBlocked:          robotsBlocked,
+		Certificates:           certs.certificates(),
 		Discovered:             len(discovered),
 		ExcludedURLs:           excluded,
 	}, nil
 }
 
+// probeSoft404 requests a random, non-existent path under root and returns a
+// fingerprint of the error page when the site answers it with 200. It returns
+// nil when the site responds with a proper error status, redirects the probe
+// (typically to the homepage, which is not an error page) or the probe fails.
+func probeSoft404(client *http.Client, root *url.URL, userAgent string) *soft404.Fingerprint {
+	probeURL, err := soft404.ProbeURL(root)
+	if err != nil {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, probeURL, nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil
+	}
+
+	finalURL, _, err := normalizeURL(resp.Request.URL.String())
+	if err != nil {
+		return nil
+	}
+	if requested, _, err := normalizeURL(probeURL); err != nil || requested != finalURL {
+		return nil
+	}
+
+	return soft404.NewFingerprint(finalURL, doc)
+}
+
+func isHTML(header http.Header) bool {
+	contentType := header.Get("Content-Type")
+	return contentType == "" || strings.Contains(strings.ToLower(contentType), "html")
+}
+
 func normalizeRoot(raw string) (string, *url.URL, error) {
 	clean := strings.TrimSpace(raw)
 	if clean == "" {
@@ -321,6 +868,9 @@ func normalizeURL(raw string) (string, *url.URL, error) {
 		return "", nil, err
 	}
 	parsed.Fragment = ""
+	// Hosts are case-insensitive; lower-case them like canonical.Normalize so
+	// crawled and canonical URLs compare equal.
+	parsed.Host = strings.ToLower(parsed.Host)
 	if parsed.Path == "" {
 		parsed.Path = "/"
 	}
@@ -349,46 +899,5 @@ func isInternal(root *url.URL, candidate *url.URL) bool {
 }
 
 func shouldExclude(link string, patterns []string) bool {
-	for _, pattern := range patterns {
-		pattern = strings.TrimSpace(pattern)
-		if pattern == "" {
-			continue
-		}
-
-		// Use path.Match (not filepath.Match) so glob behaviour is consistent
-		// across operating systems — URL paths always use forward slashes.
-		if matched, _ := pathpkg.Match(pattern, link); matched {
-			return true
-		}
-
-		parsed, err := url.Parse(link)
-		if err != nil {
-			continue
-		}
-
-		// Match against the full path (e.g. /admin/*).
-		if matched, _ := pathpkg.Match(pattern, parsed.Path); matched {
-			return true
-		}
-
-		// Match against just the filename so *.pdf matches /dir/file.pdf.
-		if matched, _ := pathpkg.Match(pattern, pathpkg.Base(parsed.Path)); matched {
-			return true
-		}
-
-		// Match path+query with and without the leading slash so that
-		// patterns like *?lang=rs work against page?lang=rs.
-		if parsed.RawQuery != "" {
-			queryPath := parsed.Path + "?" + parsed.RawQuery
-			if matched, _ := pathpkg.Match(pattern, queryPath); matched {
-				return true
-			}
-			trimmed := strings.TrimPrefix(queryPath, "/")
-			if matched, _ := pathpkg.Match(pattern, trimmed); matched {
-				return true
-			}
-		}
-	}
-
-	return false
+	return urlglob.MatchAny(link, patterns)
 }
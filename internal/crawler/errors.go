@@ -0,0 +1,108 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// ErrorClass groups failed requests by their underlying cause.
+type ErrorClass string
+
+const (
+	// ErrorDNS is a host name that could not be resolved.
+	ErrorDNS ErrorClass = "dns"
+	// ErrorRefused is a connection the server refused.
+	ErrorRefused ErrorClass = "refused"
+	// ErrorTLS is a failed TLS handshake or certificate verification.
+	ErrorTLS ErrorClass = "tls"
+	// ErrorTimeout is a request that exceeded its deadline.
+	ErrorTimeout ErrorClass = "timeout"
+	// ErrorTooManyRedirects is a redirect chain that was not followed to
+	// the end.
+	ErrorTooManyRedirects ErrorClass = "too_many_redirects"
+	// ErrorBodyTooLarge is a response larger than Options.MaxBodySize.
+	ErrorBodyTooLarge ErrorClass = "body_too_large"
+	// ErrorConnection is a connection that was reset or closed before a
+	// complete response arrived.
+	ErrorConnection ErrorClass = "connection"
+	// ErrorOther is any other failure.
+	ErrorOther ErrorClass = "other"
+)
+
+// ErrorClasses lists every ErrorClass in report order.
+var ErrorClasses = []ErrorClass{
+	ErrorDNS, ErrorRefused, ErrorTLS, ErrorTimeout, ErrorTooManyRedirects,
+	ErrorBodyTooLarge, ErrorConnection, ErrorOther,
+}
+
+// ErrBodyTooLarge is reported for responses exceeding Options.MaxBodySize.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// ParseErrorClass converts a name such as "timeout" into an ErrorClass.
+func ParseErrorClass(name string) (ErrorClass, error) {
+	class := ErrorClass(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range ErrorClasses {
+		if class == known {
+			return class, nil
+		}
+	}
+	return "", fmt.Errorf("unknown error class %q (want dns, refused, tls, timeout, too_many_redirects, body_too_large, connection or other)", name)
+}
+
+// ClassifyError returns the ErrorClass of a request that failed without an
+// HTTP response.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		verifyErr    *tls.CertificateVerificationError
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	switch {
+	case errors.Is(err, ErrBodyTooLarge):
+		return ErrorBodyTooLarge
+	case strings.Contains(err.Error(), "stopped after") && strings.Contains(err.Error(), "redirects"):
+		return ErrorTooManyRedirects
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.As(err, &verifyErr), errors.As(err, &recordErr), errors.As(err, &alertErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr),
+		strings.Contains(err.Error(), "tls: "):
+		return ErrorTLS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorRefused
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorConnection
+	}
+	return ErrorOther
+}
+
+// classifyFailure classifies a failed request from its status and error. A
+// 3xx status reaching the error handler means the redirect limit was hit;
+// other statuses are HTTP errors and have no class.
+func classifyFailure(status int, err error) ErrorClass {
+	switch {
+	case status >= 300 && status < 400:
+		return ErrorTooManyRedirects
+	case status != 0:
+		return ""
+	}
+	return ClassifyError(err)
+}
@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"nil", nil, ""},
+		{"dns", wrap(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "nope.example", IsNotFound: true}}), ErrorDNS},
+		{"dns timeout", wrap(&net.DNSError{Err: "i/o timeout", Name: "slow.example", IsTimeout: true}), ErrorDNS},
+		{"refused", wrap(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), ErrorRefused},
+		{"unknown authority", wrap(x509.UnknownAuthorityError{}), ErrorTLS},
+		{"hostname", wrap(x509.HostnameError{Certificate: &x509.Certificate{}, Host: "example.com"}), ErrorTLS},
+		{"handshake", wrap(errors.New("remote error: tls: handshake failure")), ErrorTLS},
+		{"timeout", wrap(os.ErrDeadlineExceeded), ErrorTimeout},
+		{"redirects", wrap(errors.New("stopped after 10 redirects")), ErrorTooManyRedirects},
+		{"body too large", fmt.Errorf("%w: Content-Length 20 exceeds 10 bytes", ErrBodyTooLarge), ErrorBodyTooLarge},
+		{"reset", fmt.Errorf("read: %w", syscall.ECONNRESET), ErrorConnection},
+		{"eof", wrap(io.EOF), ErrorConnection},
+		{"canceled", context.Canceled, ErrorOther},
+		{"other", errors.New("boom"), ErrorOther},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyError(tt.err); got != tt.want {
+			t.Errorf("%s: ClassifyError = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	if got := classifyFailure(302, errors.New("Found")); got != ErrorTooManyRedirects {
+		t.Errorf("classifyFailure(302) = %q, want too_many_redirects", got)
+	}
+	if got := classifyFailure(404, errors.New("Not Found")); got != "" {
+		t.Errorf("classifyFailure(404) = %q, want no class", got)
+	}
+	if got := classifyFailure(0, wrap(os.ErrDeadlineExceeded)); got != ErrorTimeout {
+		t.Errorf("classifyFailure(0, timeout) = %q, want timeout", got)
+	}
+}
+
+// wrap returns err as the HTTP client reports it.
+func wrap(err error) error {
+	return &url.Error{Op: "Get", URL: "https://example.com", Err: err}
+}
+
+func TestParseErrorClass(t *testing.T) {
+	for _, class := range ErrorClasses {
+		if got, err := ParseErrorClass(string(class)); err != nil || got != class {
+			t.Errorf("ParseErrorClass(%q) = %q, %v", class, got, err)
+		}
+	}
+	if class, err := ParseErrorClass(" Timeout "); err != nil || class != ErrorTimeout {
+		t.Errorf("ParseErrorClass(Timeout) = %q, %v", class, err)
+	}
+	if _, err := ParseErrorClass("sometimes"); err == nil {
+		t.Error("expected error for unknown class")
+	}
+}
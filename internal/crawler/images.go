@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/tariktz/gopherseo/internal/images"
+)
+
+// fetchImageMetadata requests every image in imagesByPage once, using at most
+// threads concurrent requests, and returns the observed metadata keyed by
+// image URL.
+func fetchImageMetadata(client *http.Client, userAgent string, imagesByPage map[string][]images.Image, threads int) map[string]images.Metadata {
+	unique := make(map[string]struct{})
+	for _, pageImages := range imagesByPage {
+		for _, img := range pageImages {
+			unique[img.URL] = struct{}{}
+		}
+	}
+
+	imageURLs := make([]string, 0, len(unique))
+	for u := range unique {
+		imageURLs = append(imageURLs, u)
+	}
+	sort.Strings(imageURLs)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	meta := make(map[string]images.Metadata, len(imageURLs))
+	sem := make(chan struct{}, threads)
+
+	for _, imageURL := range imageURLs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(imageURL string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			m := fetchImage(client, userAgent, imageURL)
+
+			mu.Lock()
+			meta[imageURL] = m
+			mu.Unlock()
+		}(imageURL)
+	}
+	wg.Wait()
+
+	return meta
+}
+
+// fetchImage issues a HEAD request for the image and falls back to GET when
+// the server rejects HEAD or does not announce a Content-Length.
+func fetchImage(client *http.Client, userAgent, imageURL string) images.Metadata {
+	resp, err := doImageRequest(client, http.MethodHead, userAgent, imageURL)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode < 400 && resp.ContentLength >= 0 {
+			return images.Metadata{
+				Status:      resp.StatusCode,
+				ContentType: resp.Header.Get("Content-Type"),
+				Size:        resp.ContentLength,
+			}
+		}
+	}
+
+	resp, err = doImageRequest(client, http.MethodGet, userAgent, imageURL)
+	if err != nil {
+		return images.Metadata{Size: -1, Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	m := images.Metadata{
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        -1,
+	}
+	if resp.StatusCode >= 400 {
+		return m
+	}
+
+	size, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		m.Error = err.Error()
+		return m
+	}
+	m.Size = size
+	return m
+}
+
+func doImageRequest(client *http.Client, method, userAgent, imageURL string) (*http.Response, error) {
+	req, err := http.NewRequest(method, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	return client.Do(req)
+}
@@ -1,13 +1,36 @@
 package crawler
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/tariktz/gopherseo/internal/auth"
+	"github.com/tariktz/gopherseo/internal/canonical"
+	"github.com/tariktz/gopherseo/internal/lastmod"
+	"github.com/tariktz/gopherseo/internal/throttle"
 )
 
 // newTestServer creates an httptest.Server with a small site structure:
@@ -324,3 +347,1117 @@ func TestCrawl_TrailingSlashNormalization(t *testing.T) {
 		t.Errorf("expected exactly 1 /about entry, got %d (ValidURLs: %v)", aboutCount, result.ValidURLs)
 	}
 }
+
+func TestCrawl_Soft404Detection(t *testing.T) {
+	errorPage := `<html><head><title>Shop</title></head><body>
+		<p>Sorry, we could not find what you were looking for.</p>
+	</body></html>`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path != "/" {
+			// Unknown paths are served with 200, like a misconfigured CMS.
+			_, _ = fmt.Fprint(w, errorPage)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body>
+			<a href="/about">About</a>
+			<a href="/old-product">Old product</a>
+			<a href="/gone">Gone</a>
+			<a href="/404-guide">404 guide</a>
+		</body></html>`)
+	})
+	mux.HandleFunc("/404-guide", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><title>404 error guide</title></head><body><h1>How to fix 404 errors</h1><p>Redirect broken links.</p></body></html>`)
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><title>About us</title></head><body><p>We sell shoes.</p></body></html>`)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><title>Page not found</title></head><body>
+			<p>Sorry, we could not find the page you were looking for.</p>
+		</body></html>`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{
+		RootURL:        ts.URL,
+		Threads:        2,
+		RequestTimeout: 10 * time.Second,
+		DetectSoft404:  true,
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	wantSoft404 := []string{ts.URL + "/gone", ts.URL + "/old-product"}
+	if strings.Join(result.Soft404Pages, ",") != strings.Join(wantSoft404, ",") {
+		t.Fatalf("Soft404Pages = %v, want %v", result.Soft404Pages, wantSoft404)
+	}
+
+	for _, u := range result.ValidURLs {
+		if u == ts.URL+"/gone" || u == ts.URL+"/old-product" {
+			t.Errorf("soft 404 %q should not be in ValidURLs", u)
+		}
+	}
+
+	for _, soft := range wantSoft404 {
+		if status, ok := result.BrokenLinks[soft]; !ok || status != http.StatusOK {
+			t.Errorf("BrokenLinks[%q] = %d (present=%v), want 200", soft, status, ok)
+		}
+	}
+
+	for _, task := range result.BrokenLinkTasks {
+		if task.Soft404Reason == "" {
+			t.Errorf("task for %q missing Soft404Reason", task.URL)
+		}
+	}
+	if !slices.Contains(result.ValidURLs, ts.URL+"/404-guide") {
+		t.Errorf("a page only titled like an error page should stay valid, got %v", result.ValidURLs)
+	}
+}
+
+func TestCrawl_Soft404ProbeRedirect(t *testing.T) {
+	page := `<html><head><title>Shop</title></head><body><p>Welcome to the shop</p><a href="/welcome">Welcome</a></body></html>`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			// Unknown paths redirect to the homepage.
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, page)
+	})
+	mux.HandleFunc("/welcome", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, page)
+	})
+	mux.HandleFunc("/robots.txt", http.NotFound)
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{RootURL: ts.URL, Threads: 2, DetectSoft404: true})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+	if len(result.Soft404Pages) != 0 {
+		t.Errorf("a redirected probe must not fingerprint the homepage, got soft 404s %v", result.Soft404Pages)
+	}
+}
+
+func TestCrawl_ImageAudit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body>
+			<img src="/big.jpg">
+			<img src="/small.png" alt="Small" width="10" height="10">
+			<img src="/missing.png" alt="Missing" width="10" height="10">
+			<a href="/about">About</a>
+		</body></html>`)
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body><img src="/big.jpg" alt=""></body></html>`)
+	})
+	mux.HandleFunc("/big.jpg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(make([]byte, 4096))
+	})
+	mux.HandleFunc("/small.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(make([]byte, 16))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{
+		RootURL:        ts.URL,
+		Threads:        2,
+		RequestTimeout: 10 * time.Second,
+		AuditImages:    true,
+		ImageMaxBytes:  1024,
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	if len(result.ImageAudits) != 3 {
+		t.Fatalf("ImageAudits = %d entries, want 3: %+v", len(result.ImageAudits), result.ImageAudits)
+	}
+
+	byURL := make(map[string]int)
+	for i, audit := range result.ImageAudits {
+		byURL[audit.URL] = i
+	}
+
+	big := result.ImageAudits[byURL[ts.URL+"/big.jpg"]]
+	if big.Size != 4096 || big.ContentType != "image/jpeg" || !big.Oversize {
+		t.Errorf("unexpected big.jpg audit: %+v", big)
+	}
+	if len(big.Pages) != 2 || len(big.MissingAlt) != 1 || len(big.EmptyAlt) != 1 {
+		t.Errorf("big.jpg pages/alt findings wrong: %+v", big)
+	}
+
+	small := result.ImageAudits[byURL[ts.URL+"/small.png"]]
+	if small.HasIssues() {
+		t.Errorf("small.png should have no issues: %+v", small)
+	}
+
+	missing := result.ImageAudits[byURL[ts.URL+"/missing.png"]]
+	if missing.Status != http.StatusNotFound {
+		t.Errorf("missing.png Status=%d, want 404", missing.Status)
+	}
+}
+
+func TestCrawl_CanonicalLinkHeader(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><link rel="canonical" href="/"></head><body>
+			<a href="/guide.pdf">Guide</a>
+			<a href="/conflict">Conflict</a>
+		</body></html>`)
+	})
+	mux.HandleFunc("/guide.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Link", `</guide>; rel="canonical"`)
+		_, _ = fmt.Fprint(w, "%PDF-1.4")
+	})
+	mux.HandleFunc("/conflict", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Link", `</other>; rel="canonical"`)
+		_, _ = fmt.Fprint(w, `<html><head><link rel="canonical" href="/conflict"></head></html>`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{
+		RootURL:        ts.URL,
+		Threads:        2,
+		RequestTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	if got := result.CanonicalByPage[ts.URL+"/guide.pdf"]; got != ts.URL+"/guide" {
+		t.Errorf("CanonicalByPage[pdf] = %q, want header canonical", got)
+	}
+	for _, page := range result.MissingCanonicalPages {
+		if page == ts.URL+"/guide.pdf" {
+			t.Error("PDF with Link header canonical should not be reported as missing")
+		}
+	}
+
+	found := false
+	for _, issue := range result.CanonicalIssues {
+		if issue.Type == canonical.IssueHeaderHTMLMismatch && issue.PageURL == ts.URL+"/conflict" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected header_html_mismatch for /conflict, got %+v", result.CanonicalIssues)
+	}
+}
+
+func TestCrawl_CanonicalConsistency(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /blocked\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><link rel="canonical" href="/"></head><body>
+			<a href="/variant">Variant</a>
+			<a href="/hidden">Hidden</a>
+			<a href="/to-blocked">To blocked</a>
+			<a href="/to-other-host">To other host</a>
+		</body></html>`)
+	})
+	mux.HandleFunc("/to-other-host", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><link rel="canonical" href="https://www.example.com/blocked"></head></html>`)
+	})
+	mux.HandleFunc("/variant", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><link rel="canonical" href="/hidden"></head></html>`)
+	})
+	mux.HandleFunc("/hidden", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><meta name="robots" content="noindex"><link rel="canonical" href="/hidden"></head></html>`)
+	})
+	mux.HandleFunc("/to-blocked", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><head><link rel="canonical" href="/blocked"></head></html>`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{
+		RootURL:              ts.URL,
+		Threads:              2,
+		RequestTimeout:       10 * time.Second,
+		CanonicalOnlySitemap: true,
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	types := make(map[canonical.IssueType]string)
+	for _, issue := range result.CanonicalIssues {
+		if issue.Type == canonical.IssueTargetRobotsBlocked && issue.PageURL == ts.URL+"/to-other-host" {
+			t.Errorf("another host's canonical target was judged by the root robots.txt: %+v", issue)
+			continue
+		}
+		types[issue.Type] = issue.PageURL
+	}
+	if types[canonical.IssueTargetNoindex] != ts.URL+"/variant" {
+		t.Errorf("expected target_noindex on /variant, got %+v", result.CanonicalIssues)
+	}
+	if types[canonical.IssueTargetRobotsBlocked] != ts.URL+"/to-blocked" {
+		t.Errorf("expected target_robots_blocked on /to-blocked, got %+v", result.CanonicalIssues)
+	}
+	if _, ok := types[canonical.IssueSitemapNonCanonical]; ok {
+		t.Error("canonical-only sitemap should not contain non-canonical URLs")
+	}
+
+	if len(result.NoindexPages) != 1 || result.NoindexPages[0] != ts.URL+"/hidden" {
+		t.Errorf("NoindexPages = %v, want [/hidden]", result.NoindexPages)
+	}
+
+	for _, u := range result.SitemapURLs {
+		if u == ts.URL+"/variant" || u == ts.URL+"/to-blocked" || u == ts.URL+"/to-other-host" {
+			t.Errorf("non-canonical page %q should be dropped from SitemapURLs", u)
+		}
+	}
+	if len(result.SitemapURLs) >= len(result.ValidURLs) {
+		t.Errorf("SitemapURLs (%d) should be smaller than ValidURLs (%d)", len(result.SitemapURLs), len(result.ValidURLs))
+	}
+}
+
+func TestCrawl_LastmodStore(t *testing.T) {
+	var body atomic.Value
+	body.Store("original")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprintf(w, `<html><body><main>%s</main></body></html>`, body.Load())
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	opts := Options{
+		RootURL:          ts.URL,
+		RequestTimeout:   10 * time.Second,
+		LastmodStorePath: filepath.Join(t.TempDir(), "lastmod.json"),
+	}
+
+	first, err := Crawl(opts)
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+	root := ts.URL + "/"
+	firstMod := first.LastModified[root].Time
+	if got := first.LastModified[root].Source; got != lastmod.SourceFallback {
+		t.Errorf("first crawl source = %q, want fallback", got)
+	}
+
+	second, err := Crawl(opts)
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+	if got := second.LastModified[root]; !got.Time.Equal(firstMod) || got.Source != lastmod.SourceHistory {
+		t.Errorf("unchanged page lastmod = %+v, want %v from history", got, firstMod)
+	}
+
+	body.Store("changed")
+	third, err := Crawl(opts)
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+	if got := third.LastModified[root].Time; !got.After(firstMod) {
+		t.Errorf("changed page lastmod = %v, want after %v", got, firstMod)
+	}
+}
+
+func TestCrawl_LastmodProvenance(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2023 10:00:00 GMT")
+		_, _ = fmt.Fprint(w, `<html><head>
+			<script type="application/ld+json">{"dateModified":"2025-06-15T10:30:00Z"}</script>
+		</head><body><a href="/plain">Plain</a></body></html>`)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body>Plain</body></html>`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{RootURL: ts.URL, RequestTimeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	root := result.LastModified[ts.URL+"/"]
+	if root.Source != lastmod.SourceJSONLD || len(root.Candidates) != 2 {
+		t.Errorf("root lastmod = %+v, want jsonld with 2 candidates", root)
+	}
+	if got := result.LastModified[ts.URL+"/plain"].Source; got != lastmod.SourceFallback {
+		t.Errorf("plain lastmod source = %q, want fallback", got)
+	}
+
+	report := result.LastmodReport
+	if report.Total != 2 || report.BySource[lastmod.SourceFallback] != 1 {
+		t.Errorf("unexpected report counts: %+v", report)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Type != lastmod.IssueSourceConflict {
+		t.Errorf("expected one source_conflict issue, got %+v", report.Issues)
+	}
+}
+
+func TestCrawl_LastmodSourceOrder(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2023 10:00:00 GMT")
+		_, _ = fmt.Fprint(w, `<html><head>
+			<script type="application/ld+json">{"dateModified":"2020-06-15"}</script>
+		</head><body><a href="/listed/">Listed</a></body></html>`)
+	})
+	mux.HandleFunc("/listed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body>Listed</body></html>`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	sitemapTime := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	result, err := Crawl(Options{
+		RootURL:        ts.URL,
+		RequestTimeout: 10 * time.Second,
+		LastmodSources: []lastmod.Source{lastmod.SourceHeader, lastmod.SourceJSONLD, lastmod.SourceSitemap},
+		SitemapLastmod: map[string]time.Time{ts.URL + "/listed/": sitemapTime},
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	if got := result.LastModified[ts.URL+"/"].Source; got != lastmod.SourceHeader {
+		t.Errorf("root lastmod source = %q, want header", got)
+	}
+	listed := result.LastModified[ts.URL+"/listed"]
+	if listed.Source != lastmod.SourceSitemap || !listed.Time.Equal(sitemapTime) {
+		t.Errorf("listed lastmod = %+v, want sitemap %v", listed, sitemapTime)
+	}
+}
+
+func TestCrawl_DepthByURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = fmt.Fprint(w, `<html><body><a href="/a">A</a><a href="/b">B</a></body></html>`)
+		case "/a":
+			_, _ = fmt.Fprint(w, `<html><body><a href="/a/deep">Deep</a><a href="/b">B</a></body></html>`)
+		default:
+			_, _ = fmt.Fprint(w, `<html><body>leaf</body></html>`)
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{RootURL: ts.URL, Threads: 1, RequestTimeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	want := map[string]int{
+		ts.URL + "/":       0,
+		ts.URL + "/a":      1,
+		ts.URL + "/b":      1,
+		ts.URL + "/a/deep": 2,
+	}
+	for u, depth := range want {
+		if got, ok := result.DepthByURL[u]; !ok || got != depth {
+			t.Errorf("DepthByURL[%s] = %d (ok=%v), want %d", u, got, ok, depth)
+		}
+	}
+}
+
+func TestCrawl_RobotsBlocked(t *testing.T) {
+	var robotsHits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		robotsHits.Add(1)
+		_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /private\nDisallow: /target\nCrawl-delay: 0\nSitemap: http://example.com/sitemap.xml\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = fmt.Fprint(w, `<html><body><a href="/a">A</a><a href="/b">B</a><a href="/private/x">X</a></body></html>`)
+		case "/a":
+			_, _ = fmt.Fprint(w, `<html><head><link rel="canonical" href="/target"></head><body><a href="/private/x">X</a><a href="/target">T</a></body></html>`)
+		default:
+			_, _ = fmt.Fprint(w, `<html><body><a href="/private/x">X</a><a href="/private/y">Y</a></body></html>`)
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{RootURL: ts.URL, Threads: 2, RequestTimeout: 10 * time.Second, RobotsInlinkThreshold: 3})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	if result.RobotsTxt == nil || len(result.RobotsTxt.Sitemaps) != 1 {
+		t.Fatalf("expected parsed robots.txt with one sitemap, got %+v", result.RobotsTxt)
+	}
+
+	blocked := make(map[string]RobotsBlockedLink)
+	for _, link := range result.RobotsBlocked {
+		blocked[strings.TrimPrefix(link.URL, ts.URL)] = link
+	}
+	if len(blocked) != 3 {
+		t.Fatalf("expected 3 blocked links, got %+v", result.RobotsBlocked)
+	}
+	if x := blocked["/private/x"]; len(x.Sources) != 3 || !x.Flagged || x.CanonicalTarget || x.Rule != "line 2: Disallow: /private" {
+		t.Errorf("/private/x = %+v, want 3 sources, flagged and the line 2 rule", x)
+	}
+	if y := blocked["/private/y"]; len(y.Sources) != 1 || y.Flagged {
+		t.Errorf("/private/y = %+v, want 1 source and not flagged", y)
+	}
+	if target := blocked["/target"]; !target.CanonicalTarget || !target.Flagged {
+		t.Errorf("/target = %+v, want flagged canonical target", target)
+	}
+	for _, u := range result.ValidURLs {
+		if strings.Contains(u, "/private") {
+			t.Errorf("blocked URL %q should not be crawled", u)
+		}
+	}
+	if n := robotsHits.Load(); n != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", n)
+	}
+}
+
+func TestCrawl_ThrottleBacksOffAndRetries(t *testing.T) {
+	var limitedHits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "User-agent: *\nCrawl-delay: 0.01\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body><a href="/limited">Limited</a><a href="/down">Down</a></body></html>`)
+	})
+	mux.HandleFunc("/limited", func(w http.ResponseWriter, r *http.Request) {
+		if limitedHits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body>ok</body></html>`)
+	})
+	mux.HandleFunc("/down", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	var mu sync.Mutex
+	events := make(map[throttle.EventType]int)
+	result, err := Crawl(Options{
+		RootURL:             ts.URL,
+		Threads:             2,
+		RequestTimeout:      10 * time.Second,
+		AdaptiveConcurrency: true,
+		RateLimitRetries:    2,
+		OnThrottle: func(e throttle.Event) {
+			mu.Lock()
+			events[e.Type]++
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	if _, ok := result.BrokenLinks[ts.URL+"/limited"]; ok {
+		t.Error("/limited should succeed after its 429 was retried")
+	}
+	if status := result.BrokenLinks[ts.URL+"/down"]; status != http.StatusServiceUnavailable {
+		t.Errorf("/down status = %d, want 503 after retries are exhausted", status)
+	}
+	if events[throttle.EventDelay] != 1 {
+		t.Errorf("expected one Crawl-delay event, got %v", events)
+	}
+	// One backoff for /limited, three for /down (first attempt and two retries).
+	if events[throttle.EventBackoff] != 4 {
+		t.Errorf("backoff events = %d, want 4", events[throttle.EventBackoff])
+	}
+}
+
+func TestCrawl_ThrottlesOwnRequests(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			mu.Unlock()
+		}
+		switch {
+		case r.URL.Path == "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = fmt.Fprint(w, `<html><body><img src="/a.png"><img src="/b.png"><img src="/c.png"></body></html>`)
+		case strings.HasSuffix(r.URL.Path, ".png"):
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(make([]byte, 16))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	const delay = 40 * time.Millisecond
+	_, err := Crawl(Options{
+		RootURL:       ts.URL,
+		Threads:       4,
+		Delay:         delay,
+		DetectSoft404: true,
+		AuditImages:   true,
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Soft-404 probe, root page and three images, each fetched at least once.
+	if len(starts) < 5 {
+		t.Fatalf("expected at least 5 requests, got %d", len(starts))
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < delay-5*time.Millisecond {
+			t.Errorf("requests %d and %d were %v apart, want at least %v", i-1, i, gap, delay)
+		}
+	}
+}
+
+func TestCrawl_RetryPolicy(t *testing.T) {
+	var flakyHits, slowHits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body><a href="/flaky">Flaky</a><a href="/slow">Slow</a><a href="/dead">Dead</a><a href="/missing">Missing</a></body></html>`)
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if flakyHits.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body>ok</body></html>`)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		if slowHits.Add(1) == 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+		_, _ = fmt.Fprint(w, `<html><body>ok</body></html>`)
+	})
+	mux.HandleFunc("/dead", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.Backoff = time.Millisecond
+	result, err := Crawl(Options{
+		RootURL:        ts.URL,
+		Threads:        4,
+		RequestTimeout: 100 * time.Millisecond,
+		Retry:          policy,
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	tasks := make(map[string]BrokenLinkTask)
+	for _, task := range result.BrokenLinkTasks {
+		tasks[strings.TrimPrefix(task.URL, ts.URL)] = task
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected only /dead and /missing to be broken, got %+v", result.BrokenLinkTasks)
+	}
+	if dead := tasks["/dead"]; dead.Attempts != 3 || dead.Error != "Internal Server Error" {
+		t.Errorf("/dead = %+v, want 3 attempts and the status text as error", dead)
+	}
+	if missing := tasks["/missing"]; missing.Attempts != 1 {
+		t.Errorf("/missing attempts = %d, want 1 (404 is not retried)", missing.Attempts)
+	}
+}
+
+func TestCrawl_RetryAfterRedirect(t *testing.T) {
+	var mu sync.Mutex
+	var hits []time.Time
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body><a href="/old">Old</a></body></html>`)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits = append(hits, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.Backoff = 100 * time.Millisecond
+	result, err := Crawl(Options{RootURL: ts.URL, Threads: 2, RequestTimeout: 5 * time.Second, Retry: policy})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	if len(hits) != 3 {
+		t.Fatalf("/new was requested %d times, want 3", len(hits))
+	}
+	for i := 1; i < len(hits); i++ {
+		if gap := hits[i].Sub(hits[i-1]); gap < 90*time.Millisecond {
+			t.Errorf("retry %d started %v after the previous attempt, want the backoff", i, gap)
+		}
+	}
+	if len(result.BrokenLinkTasks) != 1 || result.BrokenLinkTasks[0].Attempts != 3 {
+		t.Errorf("expected one broken task with 3 attempts, got %+v", result.BrokenLinkTasks)
+	}
+}
+
+func TestCrawl_ErrorClasses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body><a href="/big">Big</a><a href="/huge.pdf">Huge</a><a href="/loop">Loop</a><a href="/gone">Gone</a></body></html>`)
+	})
+	mux.HandleFunc("/huge.pdf", func(w http.ResponseWriter, r *http.Request) {
+		const size = 11 << 20
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		_, _ = w.Write(make([]byte, size))
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Length", "4096")
+		_, _ = fmt.Fprint(w, strings.Repeat("x", 4096))
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	result, err := Crawl(Options{RootURL: ts.URL, Threads: 2, RequestTimeout: 10 * time.Second, MaxBodySize: 1024})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	classes := make(map[string]BrokenLinkTask)
+	for _, task := range result.BrokenLinkTasks {
+		classes[strings.TrimPrefix(task.URL, ts.URL)] = task
+	}
+	if big := classes["/big"]; big.ErrorClass != ErrorBodyTooLarge || !strings.Contains(big.Error, "exceeds 1024 bytes") {
+		t.Errorf("/big = %+v, want body_too_large", big)
+	}
+	if loop := classes["/loop"]; loop.ErrorClass != ErrorTooManyRedirects || loop.Status != http.StatusFound {
+		t.Errorf("/loop = %+v, want too_many_redirects with status 302", loop)
+	}
+	if gone := classes["/gone"]; gone.ErrorClass != "" || gone.Status != http.StatusGone {
+		t.Errorf("/gone = %+v, want HTTP 410 without a class", gone)
+	}
+
+	// Without MaxBodySize large downloads are truncated, not reported.
+	result, err = Crawl(Options{RootURL: ts.URL, Threads: 2, RequestTimeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+	for _, task := range result.BrokenLinkTasks {
+		if task.ErrorClass == ErrorBodyTooLarge {
+			t.Errorf("%s reported as body_too_large without MaxBodySize", task.URL)
+		}
+	}
+	if !slices.Contains(result.ValidURLs, ts.URL+"/huge.pdf") {
+		t.Errorf("expected /huge.pdf to stay valid, got %v", result.ValidURLs)
+	}
+}
+
+func TestCrawl_Auth(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body><a href="/members">Members</a></body></html>`)
+	})
+	mux.HandleFunc("/members", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("sid"); err != nil {
+			http.Error(w, "login required", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body>members</body></html>`)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("password") != "pw" {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "1", Path: "/"})
+	})
+
+	var unauthorized atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "staging" || pass != "secret" || r.Header.Get("X-Env") != "staging" {
+			unauthorized.Add(1)
+			w.Header().Set("WWW-Authenticate", `Basic realm="staging"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	result, err := Crawl(Options{
+		RootURL: ts.URL,
+		Threads: 2,
+		Auth: auth.Options{
+			Username: "staging",
+			Password: "secret",
+			Header:   http.Header{"X-Env": {"staging"}},
+			Login:    &auth.FormLogin{URL: "/login", Fields: url.Values{"password": {"pw"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+	if len(result.BrokenLinks) != 0 {
+		t.Errorf("expected no broken links, got %v", result.BrokenLinks)
+	}
+	if len(result.ValidURLs) != 2 {
+		t.Errorf("expected / and /members, got %v", result.ValidURLs)
+	}
+	if n := unauthorized.Load(); n != 0 {
+		t.Errorf("%d requests were sent without credentials", n)
+	}
+
+	_, err = Crawl(Options{
+		RootURL: ts.URL,
+		Auth: auth.Options{
+			Username: "staging",
+			Password: "secret",
+			Header:   http.Header{"X-Env": {"staging"}},
+			Login:    &auth.FormLogin{URL: "/login", Fields: url.Values{"password": {"wrong"}}},
+		},
+	})
+	if err == nil {
+		t.Error("expected a failed login to abort the crawl")
+	}
+}
+
+func TestCrawl_Resolve(t *testing.T) {
+	var hosts sync.Map
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts.Store(r.Host, true)
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = fmt.Fprintf(w, `<html><head><link rel="canonical" href="http://%s/"></head><body><a href="/about">About</a></body></html>`, r.Host)
+		case "/about":
+			_, _ = fmt.Fprint(w, `<html><body>about</body></html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatalf("split test server address: %v", err)
+	}
+	root := "http://www.example.test:" + port
+
+	result, err := Crawl(Options{
+		RootURL: root,
+		Threads: 2,
+		Resolve: []Resolve{{Host: "www.example.test", Port: port, Addresses: []string{"127.0.0.1"}}},
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	sort.Strings(result.ValidURLs)
+	want := []string{root + "/", root + "/about"}
+	if strings.Join(result.ValidURLs, " ") != strings.Join(want, " ") {
+		t.Errorf("ValidURLs = %v, want %v", result.ValidURLs, want)
+	}
+	hosts.Range(func(host, _ any) bool {
+		if host != "www.example.test:"+port {
+			t.Errorf("request sent with Host %v", host)
+		}
+		return true
+	})
+	if len(result.CanonicalIssues) != 0 {
+		t.Errorf("expected no canonical issues, got %+v", result.CanonicalIssues)
+	}
+}
+
+func TestCrawl_MixedCaseRootHost(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		host := strings.ToLower(r.Host)
+		switch r.URL.Path {
+		case "/":
+			_, _ = fmt.Fprintf(w, `<html><head><link rel="canonical" href="http://%s/"></head><body><a href="/about">About</a></body></html>`, host)
+		case "/about":
+			_, _ = fmt.Fprintf(w, `<html><head><link rel="canonical" href="http://%s/about"></head><body>about</body></html>`, host)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatalf("split test server address: %v", err)
+	}
+
+	result, err := Crawl(Options{
+		RootURL:              "http://WWW.Example.test:" + port,
+		Threads:              2,
+		Resolve:              []Resolve{{Host: "www.example.test", Port: port, Addresses: []string{"127.0.0.1"}}},
+		CanonicalOnlySitemap: true,
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+
+	root := "http://www.example.test:" + port
+	want := []string{root + "/", root + "/about"}
+	sort.Strings(result.SitemapURLs)
+	if strings.Join(result.SitemapURLs, " ") != strings.Join(want, " ") {
+		t.Errorf("SitemapURLs = %v, want %v", result.SitemapURLs, want)
+	}
+	if len(result.CanonicalIssues) != 0 {
+		t.Errorf("expected no canonical issues, got %+v", result.CanonicalIssues)
+	}
+}
+
+func TestCrawl_TLS(t *testing.T) {
+	var clientCerts atomic.Int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			clientCerts.Add(1)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, `<html><body>ok</body></html>`)
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.StartTLS()
+	defer ts.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", ts.Certificate().Raw)
+
+	t.Run("untrusted", func(t *testing.T) {
+		_, err := Crawl(Options{RootURL: ts.URL})
+		if err == nil || ClassifyError(err) != ErrorTLS {
+			t.Errorf("expected the crawl to fail with a TLS error, got %v", err)
+		}
+	})
+
+	t.Run("ca file", func(t *testing.T) {
+		result, err := Crawl(Options{RootURL: ts.URL, TLS: TLSOptions{CAFile: caFile}})
+		if err != nil {
+			t.Fatalf("Crawl() error: %v", err)
+		}
+		if len(result.BrokenLinks) != 0 {
+			t.Fatalf("expected no broken links, got %v", result.BrokenLinks)
+		}
+		if len(result.Certificates) != 1 {
+			t.Fatalf("expected one certificate, got %+v", result.Certificates)
+		}
+		cert := result.Certificates[0]
+		if cert.Host != "127.0.0.1" || !cert.NotAfter.Equal(ts.Certificate().NotAfter) || !strings.Contains(cert.Issuer, "Acme Co") {
+			t.Errorf("certificate = %+v", cert)
+		}
+		if strings.Join(cert.DNSNames, ",") != strings.Join(ts.Certificate().DNSNames, ",") {
+			t.Errorf("DNSNames = %v, want %v", cert.DNSNames, ts.Certificate().DNSNames)
+		}
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		result, err := Crawl(Options{RootURL: ts.URL, TLS: TLSOptions{InsecureSkipVerify: true}})
+		if err != nil {
+			t.Fatalf("Crawl() error: %v", err)
+		}
+		if len(result.BrokenLinks) != 0 || len(result.Certificates) != 1 {
+			t.Errorf("expected a successful crawl with one certificate, got %v, %+v", result.BrokenLinks, result.Certificates)
+		}
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		certFile, keyFile := writeClientCertificate(t, dir)
+		clientCerts.Store(0)
+		result, err := Crawl(Options{RootURL: ts.URL, TLS: TLSOptions{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}})
+		if err != nil {
+			t.Fatalf("Crawl() error: %v", err)
+		}
+		if len(result.BrokenLinks) != 0 || clientCerts.Load() == 0 {
+			t.Errorf("expected the client certificate to be presented, broken links %v", result.BrokenLinks)
+		}
+
+		if _, err := Crawl(Options{RootURL: ts.URL, TLS: TLSOptions{CertFile: certFile}}); err == nil {
+			t.Error("expected an error for a certificate without a key")
+		}
+	})
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func writeClientCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "crawler"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func TestCrawl_Proxies(t *testing.T) {
+	// Each proxy serves the site itself, so requests only succeed when they
+	// are sent through a proxy.
+	newProxy := func(hits *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !r.URL.IsAbs() || r.URL.Host != "site.example.test" {
+				http.Error(w, "not a proxy request", http.StatusBadRequest)
+				return
+			}
+			hits.Add(1)
+			w.Header().Set("Content-Type", "text/html")
+			switch r.URL.Path {
+			case "/":
+				_, _ = fmt.Fprint(w, `<html><body><a href="/a">A</a><a href="/b">B</a><a href="/c">C</a></body></html>`)
+			case "/a", "/b", "/c":
+				_, _ = fmt.Fprint(w, `<html><body>page</body></html>`)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	}
+	var hitsA, hitsB atomic.Int32
+	proxyA, proxyB := newProxy(&hitsA), newProxy(&hitsB)
+	defer proxyA.Close()
+	defer proxyB.Close()
+
+	result, err := Crawl(Options{
+		RootURL: "http://site.example.test/",
+		Threads: 2,
+		Proxies: []string{proxyA.URL, proxyB.URL},
+	})
+	if err != nil {
+		t.Fatalf("Crawl() error: %v", err)
+	}
+	if len(result.ValidURLs) != 4 || len(result.BrokenLinks) != 0 {
+		t.Errorf("expected 4 valid URLs and no broken links, got %v and %v", result.ValidURLs, result.BrokenLinks)
+	}
+	if hitsA.Load() == 0 || hitsB.Load() == 0 {
+		t.Errorf("expected requests through both proxies, got %d and %d", hitsA.Load(), hitsB.Load())
+	}
+
+	if _, err := Crawl(Options{RootURL: "http://site.example.test/", Proxies: []string{"ftp://proxy.internal"}}); err == nil {
+		t.Error("expected an error for an unsupported proxy scheme")
+	}
+}
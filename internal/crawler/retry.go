@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"slices"
+	"time"
+)
+
+// RetryPolicy decides which failed requests are retried before a URL is
+// reported as broken. 429 and 503 responses are handled by the throttle
+// (Options.RateLimitRetries) instead.
+type RetryPolicy struct {
+	// Attempts is the maximum number of requests per URL, including the
+	// first. Values below 2 disable retries.
+	Attempts int
+	// Backoff is the wait before the first retry; it doubles for every
+	// further retry.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between retries. Zero means no cap.
+	MaxBackoff time.Duration
+	// Statuses lists the HTTP status codes that are retried.
+	Statuses []int
+	// Errors lists the classes of request failures (no HTTP response) that
+	// are retried.
+	Errors []ErrorClass
+}
+
+// DefaultRetryPolicy retries timeouts, refused and dropped connections and
+// 500/502/504 responses up to three attempts with a one-second initial
+// backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts:   3,
+		Backoff:    time.Second,
+		MaxBackoff: 30 * time.Second,
+		Statuses:   []int{500, 502, 504},
+		Errors:     []ErrorClass{ErrorTimeout, ErrorRefused, ErrorConnection},
+	}
+}
+
+// retryable reports whether a request that failed with status and err
+// should be retried after attempts requests.
+func (p RetryPolicy) retryable(status int, err error, attempts int) bool {
+	if attempts >= p.Attempts {
+		return false
+	}
+	if class := classifyFailure(status, err); class != "" {
+		return slices.Contains(p.Errors, class)
+	}
+	return slices.Contains(p.Statuses, status)
+}
+
+// backoff returns the wait before retry number retry (1-based).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.Backoff << min(retry-1, 20)
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,44 @@
+package crawler
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	p := DefaultRetryPolicy()
+	timeout := &url.Error{Op: "Get", URL: "https://example.com", Err: os.ErrDeadlineExceeded}
+
+	if !p.retryable(0, timeout, 1) {
+		t.Error("timeout on the first attempt should be retried")
+	}
+	if p.retryable(0, timeout, 3) {
+		t.Error("no retry after the last attempt")
+	}
+	if !p.retryable(502, errors.New("Bad Gateway"), 1) {
+		t.Error("502 should be retried")
+	}
+	if p.retryable(404, errors.New("Not Found"), 1) {
+		t.Error("404 should not be retried")
+	}
+	if p.retryable(301, errors.New("Moved Permanently"), 1) {
+		t.Error("an unfinished redirect chain should not be retried by default")
+	}
+	if p.retryable(0, errors.New("boom"), 1) {
+		t.Error("unclassified errors should not be retried by default")
+	}
+	if (RetryPolicy{}).retryable(502, nil, 1) {
+		t.Error("zero policy should never retry")
+	}
+
+	p.Backoff = time.Second
+	p.MaxBackoff = 3 * time.Second
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second} {
+		if got := p.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/tariktz/gopherseo/internal/robots"
+)
+
+// fetchRobots downloads and parses robots.txt for root. The file is nil,
+// which allows everything, when the server does not return it or it cannot
+// be parsed. An error means the host could not be reached at all.
+func fetchRobots(client *http.Client, root *url.URL, userAgent string) (*robots.File, error) {
+	robotsURL := url.URL{Scheme: root.Scheme, Host: root.Host, Path: "/robots.txt"}
+
+	req, err := http.NewRequest(http.MethodGet, robotsURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+
+	file, err := robots.Parse(resp.Body)
+	if err != nil {
+		return nil, nil
+	}
+	return file, nil
+}
+
+// robotsRule describes the rule behind a robots.txt match.
+func robotsRule(match robots.Match) string {
+	if match.Rule == nil {
+		return ""
+	}
+	return fmt.Sprintf("line %d: %s", match.Rule.Line, match.Rule)
+}
+
+// buildRobotsBlocked turns the robots-blocked links and the rules that
+// blocked them into sorted entries with their source pages, flagging
+// canonical targets and links with at least threshold source pages.
+func buildRobotsBlocked(blocked map[string]string, sources map[string]map[string]struct{}, canonicalByPage map[string]string, threshold int) []RobotsBlockedLink {
+	if threshold <= 0 {
+		threshold = DefaultRobotsInlinkThreshold
+	}
+
+	targets := make(map[string]struct{}, len(canonicalByPage))
+	for page, target := range canonicalByPage {
+		if target != page {
+			targets[target] = struct{}{}
+		}
+	}
+
+	links := make([]RobotsBlockedLink, 0, len(blocked))
+	for u, rule := range blocked {
+		sourceList := make([]string, 0, len(sources[u]))
+		for source := range sources[u] {
+			sourceList = append(sourceList, source)
+		}
+		sort.Strings(sourceList)
+
+		_, isTarget := targets[u]
+		links = append(links, RobotsBlockedLink{
+			URL:             u,
+			Sources:         sourceList,
+			Rule:            rule,
+			CanonicalTarget: isTarget,
+			Flagged:         isTarget || len(sourceList) >= threshold,
+		})
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].URL < links[j].URL })
+	return links
+}
@@ -0,0 +1,269 @@
+package crawler
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tariktz/gopherseo/internal/auth"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Resolve sends connections for Host and Port to fixed addresses instead of
+// the addresses DNS returns, like curl's --resolve. URLs, Host headers and
+// TLS server names keep Host.
+type Resolve struct {
+	Host string
+	Port string
+	// Addresses are IP addresses, tried in order.
+	Addresses []string
+}
+
+// ParseResolve parses a curl-style "host:port:address[,address...]" value.
+// IPv6 addresses may be enclosed in brackets.
+func ParseResolve(value string) (Resolve, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return Resolve{}, fmt.Errorf("invalid resolve %q: expected host:port:address", value)
+	}
+	if port, err := strconv.Atoi(parts[1]); err != nil || port < 1 || port > 65535 {
+		return Resolve{}, fmt.Errorf("invalid resolve %q: bad port %q", value, parts[1])
+	}
+
+	r := Resolve{Host: strings.ToLower(parts[0]), Port: parts[1]}
+	for _, address := range strings.Split(parts[2], ",") {
+		address = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(address), "["), "]")
+		if net.ParseIP(address) == nil {
+			return Resolve{}, fmt.Errorf("invalid resolve %q: %q is not an IP address", value, address)
+		}
+		r.Addresses = append(r.Addresses, address)
+	}
+	return r, nil
+}
+
+// TLSOptions configures certificate verification and client certificates
+// for every HTTPS request of a crawl.
+type TLSOptions struct {
+	// CAFile is a PEM bundle of root certificates trusted in addition to the
+	// system roots, e.g. a private CA.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and private key
+	// presented to servers that request one (mutual TLS).
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify disables certificate verification. Certificates are
+	// still recorded in Result.Certificates.
+	InsecureSkipVerify bool
+}
+
+// Certificate describes the leaf certificate a host presented.
+type Certificate struct {
+	Host      string
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	// DNSNames are the certificate's DNS subject alternative names.
+	DNSNames []string
+}
+
+// tlsConfig builds the client TLS configuration for opts.
+func tlsConfig(opts TLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+
+	if opts.CAFile != "" {
+		data, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file %s", opts.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, errors.New("a client certificate needs both a certificate and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// certificateRecorder is an http.RoundTripper that keeps the first leaf
+// certificate each host presented.
+type certificateRecorder struct {
+	base http.RoundTripper
+
+	mu     sync.Mutex
+	byHost map[string]Certificate
+}
+
+func newCertificateRecorder(base http.RoundTripper) *certificateRecorder {
+	return &certificateRecorder{base: base, byHost: make(map[string]Certificate)}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *certificateRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil || resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return resp, err
+	}
+
+	host := strings.ToLower(req.URL.Hostname())
+	leaf := resp.TLS.PeerCertificates[0]
+	r.mu.Lock()
+	if _, ok := r.byHost[host]; !ok {
+		r.byHost[host] = Certificate{
+			Host:      host,
+			Subject:   leaf.Subject.String(),
+			Issuer:    leaf.Issuer.String(),
+			NotBefore: leaf.NotBefore,
+			NotAfter:  leaf.NotAfter,
+			DNSNames:  append([]string(nil), leaf.DNSNames...),
+		}
+	}
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// certificates returns the recorded certificates sorted by host.
+func (r *certificateRecorder) certificates() []Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	certs := make([]Certificate, 0, len(r.byHost))
+	for _, cert := range r.byHost {
+		certs = append(certs, cert)
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Host < certs[j].Host })
+	return certs
+}
+
+// proxyFunc returns a transport Proxy function that rotates through proxies
+// on every request. Proxies without a scheme use http://. Hosts matched by
+// noProxy (NO_PROXY syntax) and loopback hosts are reached directly.
+func proxyFunc(proxies []string, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	urls := make([]*url.URL, 0, len(proxies))
+	for _, raw := range proxies {
+		raw = strings.TrimSpace(raw)
+		if !strings.Contains(raw, "://") {
+			raw = "http://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", raw, err)
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("invalid proxy %q: unsupported scheme %q (want http, https or socks5)", raw, u.Scheme)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q: missing host", raw)
+		}
+		urls = append(urls, u)
+	}
+
+	// httpproxy applies the NO_PROXY rules; the placeholder proxy only tells
+	// a bypassed host (nil) from a proxied one.
+	placeholder := "http://proxy.invalid"
+	bypass := (&httpproxy.Config{HTTPProxy: placeholder, HTTPSProxy: placeholder, NoProxy: noProxy}).ProxyFunc()
+
+	// colly's proxy.RoundRobinProxySwitcher rewrites the request inside the
+	// transport, which races with net/http, so rotate without touching it.
+	var next atomic.Uint64
+	return func(req *http.Request) (*url.URL, error) {
+		if u, err := bypass(req.URL); err != nil || u == nil {
+			return nil, err
+		}
+		return urls[(next.Add(1)-1)%uint64(len(urls))], nil
+	}, nil
+}
+
+// newTransport returns the transport and cookie jar shared by the collector
+// and the crawler's own requests (robots.txt, soft-404 probe, images), so
+// every request carries the same credentials and connection settings. The
+// returned recorder collects the server certificates.
+func newTransport(root *url.URL, opts Options) (http.RoundTripper, http.CookieJar, *certificateRecorder, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	tlsCfg, err := tlsConfig(opts.TLS)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	base.TLSClientConfig = tlsCfg
+	if len(opts.Proxies) > 0 {
+		proxy, err := proxyFunc(opts.Proxies, httpproxy.FromEnvironment().NoProxy)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		base.Proxy = proxy
+	}
+	if len(opts.Resolve) > 0 {
+		// Same dialer settings as http.DefaultTransport.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		base.DialContext = resolvingDialer(dialer, opts.Resolve)
+	}
+
+	certs := newCertificateRecorder(base)
+	transport, err := auth.NewTransport(certs, root, opts.Auth)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	jar, err := auth.NewJar(root, opts.Auth)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return transport, jar, certs, nil
+}
+
+// resolvingDialer dials the overridden addresses for host:port pairs listed
+// in overrides and everything else through dialer.
+func resolvingDialer(dialer *net.Dialer, overrides []Resolve) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	byAddr := make(map[string][]string, len(overrides))
+	for _, r := range overrides {
+		key := net.JoinHostPort(strings.ToLower(r.Host), r.Port)
+		byAddr[key] = append(byAddr[key], r.Addresses...)
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		addresses, ok := byAddr[net.JoinHostPort(strings.ToLower(host), port)]
+		if !ok {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		var errs []error
+		for _, address := range addresses {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(address, port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
@@ -0,0 +1,69 @@
+package crawler
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseResolve(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Resolve
+	}{
+		{"Example.com:443:10.0.0.5", Resolve{Host: "example.com", Port: "443", Addresses: []string{"10.0.0.5"}}},
+		{"example.com:80:10.0.0.5,10.0.0.6", Resolve{Host: "example.com", Port: "80", Addresses: []string{"10.0.0.5", "10.0.0.6"}}},
+		{"example.com:443:[2001:db8::1]", Resolve{Host: "example.com", Port: "443", Addresses: []string{"2001:db8::1"}}},
+		{"example.com:443:2001:db8::1", Resolve{Host: "example.com", Port: "443", Addresses: []string{"2001:db8::1"}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseResolve(tt.value)
+		if err != nil {
+			t.Errorf("ParseResolve(%q): %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseResolve(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"example.com", "example.com:443", ":443:10.0.0.5", "example.com:https:10.0.0.5", "example.com:0:10.0.0.5", "example.com:443:staging.internal"} {
+		if _, err := ParseResolve(bad); err == nil {
+			t.Errorf("ParseResolve(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestProxyFunc(t *testing.T) {
+	proxy, err := proxyFunc([]string{"proxy-a.internal:3128", "socks5://user:pw@proxy-b.internal:1080"}, "intranet.example.com,.corp.example")
+	if err != nil {
+		t.Fatalf("proxyFunc: %v", err)
+	}
+
+	var got []string
+	for range 3 {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+		u, err := proxy(req)
+		if err != nil {
+			t.Fatalf("proxy: %v", err)
+		}
+		got = append(got, u.String())
+	}
+	want := []string{"http://proxy-a.internal:3128", "socks5://user:pw@proxy-b.internal:1080", "http://proxy-a.internal:3128"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("proxies = %v, want %v", got, want)
+	}
+
+	for _, direct := range []string{"https://intranet.example.com/", "http://wiki.corp.example/page", "http://localhost:8080/"} {
+		req, _ := http.NewRequest(http.MethodGet, direct, nil)
+		if u, err := proxy(req); err != nil || u != nil {
+			t.Errorf("proxy(%s) = %v, %v, want a direct connection", direct, u, err)
+		}
+	}
+
+	for _, bad := range []string{"ftp://proxy.internal:21", "http://", "http://proxy internal"} {
+		if _, err := proxyFunc([]string{bad}, ""); err == nil {
+			t.Errorf("proxyFunc(%q): expected an error", bad)
+		}
+	}
+}
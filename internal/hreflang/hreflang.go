@@ -0,0 +1,216 @@
+// Package hreflang extracts hreflang alternates from HTML and HTTP Link
+// headers and validates them across a crawl: language/region codes, return
+// tags, self-references, x-default presence, and alternate targets that are
+// broken, redirecting or non-canonical.
+package hreflang
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tariktz/gopherseo/internal/canonical"
+	"github.com/tariktz/gopherseo/internal/linkheader"
+)
+
+// Source identifies where an alternate was declared.
+type Source string
+
+const (
+	SourceHTML   Source = "html"
+	SourceHeader Source = "header"
+)
+
+// Alternate is a single hreflang annotation declared by a page.
+type Alternate struct {
+	// Hreflang is the trimmed hreflang value as declared.
+	Hreflang string
+	// URL is the absolute, normalized alternate URL.
+	URL    string
+	Source Source
+}
+
+// IssueType describes an hreflang validation problem category.
+type IssueType string
+
+const (
+	IssueInvalidCode          IssueType = "invalid_code"
+	IssueMissingReturnTag     IssueType = "missing_return_tag"
+	IssueMissingSelfReference IssueType = "missing_self_reference"
+	IssueMissingXDefault      IssueType = "missing_x_default"
+	IssueConflictingCode      IssueType = "conflicting_code"
+	IssueTargetBroken         IssueType = "target_broken"
+	IssueTargetRedirect       IssueType = "target_redirect"
+	IssueTargetNonCanonical   IssueType = "target_non_canonical"
+)
+
+// Issue represents an hreflang validation finding for a page.
+type Issue struct {
+	PageURL   string
+	Hreflang  string
+	TargetURL string
+	Type      IssueType
+	Detail    string
+}
+
+// Extract returns the hreflang alternates declared by a page via
+// <link rel="alternate" hreflang> elements and the HTTP Link header. Relative
+// URLs are resolved against pageURL; duplicate declarations are collapsed.
+func Extract(pageURL string, header http.Header, doc *goquery.Document) []Alternate {
+	alternates := make([]Alternate, 0)
+	seen := make(map[string]struct{})
+
+	add := func(lang, href string, source Source) {
+		lang = strings.TrimSpace(lang)
+		href = strings.TrimSpace(href)
+		if lang == "" || href == "" {
+			return
+		}
+		resolved, ok := canonical.Resolve(pageURL, href)
+		if !ok {
+			return
+		}
+		key := strings.ToLower(lang) + "|" + resolved
+		if _, dup := seen[key]; dup {
+			return
+		}
+		seen[key] = struct{}{}
+		alternates = append(alternates, Alternate{Hreflang: lang, URL: resolved, Source: source})
+	}
+
+	if doc != nil {
+		doc.Find(`link[hreflang]`).Each(func(_ int, s *goquery.Selection) {
+			if !hasRel(s.AttrOr("rel", ""), "alternate") {
+				return
+			}
+			add(s.AttrOr("hreflang", ""), s.AttrOr("href", ""), SourceHTML)
+		})
+	}
+
+	for _, link := range linkheader.FromHeader(header) {
+		if !link.HasRel("alternate") {
+			continue
+		}
+		if lang, ok := link.Params["hreflang"]; ok {
+			add(lang, link.URL, SourceHeader)
+		}
+	}
+
+	return alternates
+}
+
+// Validate applies hreflang validation rules across all crawled pages.
+// statusByURL holds HTTP status codes gathered during the crawl and
+// canonicalByPage the extracted canonical URL per page. Return-tag checks are
+// only applied to alternates that were crawled.
+func Validate(alternatesByPage map[string][]Alternate, statusByURL map[string]int, canonicalByPage map[string]string) []Issue {
+	issues := make([]Issue, 0)
+
+	for page, alternates := range alternatesByPage {
+		if len(alternates) == 0 {
+			continue
+		}
+
+		hasSelf, hasXDefault := false, false
+		urlsByCode := make(map[string]map[string]struct{})
+
+		for _, alt := range alternates {
+			code := strings.ToLower(alt.Hreflang)
+			if code == XDefault {
+				hasXDefault = true
+			}
+			if alt.URL == page {
+				hasSelf = true
+			}
+			if _, ok := urlsByCode[code]; !ok {
+				urlsByCode[code] = make(map[string]struct{})
+			}
+			urlsByCode[code][alt.URL] = struct{}{}
+
+			if !ValidCode(alt.Hreflang) {
+				issues = append(issues, Issue{PageURL: page, Hreflang: alt.Hreflang, TargetURL: alt.URL, Type: IssueInvalidCode,
+					Detail: "hreflang value is not a valid ISO 639-1 language (optionally with ISO 3166-1 region) or x-default"})
+			}
+
+			if status, ok := statusByURL[alt.URL]; ok {
+				switch {
+				case status >= 300 && status < 400:
+					issues = append(issues, Issue{PageURL: page, Hreflang: alt.Hreflang, TargetURL: alt.URL, Type: IssueTargetRedirect,
+						Detail: "alternate target responds with redirect"})
+				case status == 0 || status >= 400:
+					issues = append(issues, Issue{PageURL: page, Hreflang: alt.Hreflang, TargetURL: alt.URL, Type: IssueTargetBroken,
+						Detail: "alternate target is broken/unreachable"})
+				}
+			}
+
+			if target, ok := canonicalByPage[alt.URL]; ok && target != alt.URL {
+				issues = append(issues, Issue{PageURL: page, Hreflang: alt.Hreflang, TargetURL: alt.URL, Type: IssueTargetNonCanonical,
+					Detail: fmt.Sprintf("alternate target canonicalizes to %s", target)})
+			}
+
+			if alt.URL != page {
+				if targetAlternates, crawled := alternatesByPage[alt.URL]; crawled || isOK(statusByURL, alt.URL) {
+					if !pointsTo(targetAlternates, page) {
+						issues = append(issues, Issue{PageURL: page, Hreflang: alt.Hreflang, TargetURL: alt.URL, Type: IssueMissingReturnTag,
+							Detail: "alternate target does not link back to this page"})
+					}
+				}
+			}
+		}
+
+		for code, urls := range urlsByCode {
+			if len(urls) > 1 {
+				issues = append(issues, Issue{PageURL: page, Hreflang: code, Type: IssueConflictingCode,
+					Detail: fmt.Sprintf("hreflang value points to %d different URLs", len(urls))})
+			}
+		}
+		if !hasSelf {
+			issues = append(issues, Issue{PageURL: page, Type: IssueMissingSelfReference,
+				Detail: "page does not list itself among its hreflang alternates"})
+		}
+		if !hasXDefault {
+			issues = append(issues, Issue{PageURL: page, Type: IssueMissingXDefault,
+				Detail: "no x-default alternate declared"})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].PageURL != issues[j].PageURL {
+			return issues[i].PageURL < issues[j].PageURL
+		}
+		if issues[i].Type != issues[j].Type {
+			return issues[i].Type < issues[j].Type
+		}
+		if issues[i].Hreflang != issues[j].Hreflang {
+			return issues[i].Hreflang < issues[j].Hreflang
+		}
+		return issues[i].TargetURL < issues[j].TargetURL
+	})
+
+	return issues
+}
+
+func isOK(statusByURL map[string]int, u string) bool {
+	status, ok := statusByURL[u]
+	return ok && status >= 200 && status < 300
+}
+
+func pointsTo(alternates []Alternate, page string) bool {
+	for _, alt := range alternates {
+		if alt.URL == page {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRel(rel, want string) bool {
+	for _, r := range strings.Fields(rel) {
+		if strings.EqualFold(r, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package hreflang
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func docFromHTML(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("build document: %v", err)
+	}
+	return doc
+}
+
+func TestValidCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"en", true},
+		{"en-GB", true},
+		{"de-at", true},
+		{"zh-Hant", true},
+		{"zh-Hant-TW", true},
+		{"x-default", true},
+		{"en-UK", false},
+		{"en_US", false},
+		{"english", false},
+		{"eu-ES-x", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidCode(tt.code); got != tt.want {
+			t.Errorf("ValidCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestExtract_HTMLAndHeader(t *testing.T) {
+	doc := docFromHTML(t, `<html><head>
+		<link rel="alternate" hreflang="en" href="/en/">
+		<link rel="alternate" hreflang="de" href="https://example.com/de/#top">
+		<link rel="alternate" hreflang="en" href="/en">
+		<link rel="stylesheet" hreflang="fr" href="/fr.css">
+	</head></html>`)
+	header := http.Header{}
+	header.Set("Link", `<https://Example.COM/fr>; rel="alternate"; hreflang="fr", <https://example.com/>; rel="canonical"`)
+
+	got := Extract("https://example.com/en", header, doc)
+	if len(got) != 3 {
+		t.Fatalf("got %d alternates, want 3: %+v", len(got), got)
+	}
+	if got[0].URL != "https://example.com/en" || got[0].Source != SourceHTML {
+		t.Errorf("unexpected first alternate: %+v", got[0])
+	}
+	if got[1].URL != "https://example.com/de" {
+		t.Errorf("URL=%q, want normalized de URL", got[1].URL)
+	}
+	if got[2].Hreflang != "fr" || got[2].URL != "https://example.com/fr" || got[2].Source != SourceHeader {
+		t.Errorf("unexpected header alternate: %+v", got[2])
+	}
+}
+
+func issueTypes(issues []Issue, page string) []string {
+	types := make([]string, 0)
+	for _, issue := range issues {
+		if issue.PageURL == page {
+			types = append(types, string(issue.Type))
+		}
+	}
+	return types
+}
+
+func TestValidate_CompleteCluster(t *testing.T) {
+	cluster := []Alternate{
+		{Hreflang: "en", URL: "https://example.com/en"},
+		{Hreflang: "de", URL: "https://example.com/de"},
+		{Hreflang: "x-default", URL: "https://example.com/en"},
+	}
+	byPage := map[string][]Alternate{
+		"https://example.com/en": cluster,
+		"https://example.com/de": cluster,
+	}
+	status := map[string]int{"https://example.com/en": 200, "https://example.com/de": 200}
+
+	if issues := Validate(byPage, status, nil); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestValidate_Findings(t *testing.T) {
+	byPage := map[string][]Alternate{
+		"https://example.com/en": {
+			{Hreflang: "en-UK", URL: "https://example.com/uk"},
+			{Hreflang: "de", URL: "https://example.com/de"},
+			{Hreflang: "fr", URL: "https://example.com/fr"},
+			{Hreflang: "es", URL: "https://example.com/es"},
+			{Hreflang: "es", URL: "https://example.com/es-2"},
+		},
+	}
+	status := map[string]int{
+		"https://example.com/en": 200,
+		"https://example.com/de": 200,
+		"https://example.com/fr": 404,
+		"https://example.com/es": 200,
+	}
+	canonicalByPage := map[string]string{
+		"https://example.com/es": "https://example.com/es/home",
+	}
+
+	issues := Validate(byPage, status, canonicalByPage)
+	got := strings.Join(issueTypes(issues, "https://example.com/en"), ",")
+
+	for _, want := range []IssueType{
+		IssueInvalidCode,
+		IssueMissingReturnTag,
+		IssueMissingSelfReference,
+		IssueMissingXDefault,
+		IssueConflictingCode,
+		IssueTargetBroken,
+		IssueTargetNonCanonical,
+	} {
+		if !strings.Contains(got, string(want)) {
+			t.Errorf("missing issue %q in %s", want, got)
+		}
+	}
+
+	// Targets that were not crawled cannot be checked for return tags.
+	for _, issue := range issues {
+		if issue.Type == IssueMissingReturnTag && issue.TargetURL == "https://example.com/uk" {
+			t.Error("uncrawled alternate should not produce a missing return tag issue")
+		}
+	}
+}
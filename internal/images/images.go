@@ -0,0 +1,258 @@
+// Package images extracts <img> elements from crawled pages, aggregates them
+// into per-image SEO audits (alt text, explicit dimensions and weight) and
+// selects the images listed in the image sitemap extension.
+package images
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Image is a single <img> occurrence on a crawled page.
+type Image struct {
+	// URL is the absolute image URL resolved against the page URL.
+	URL string
+	// Alt is the trimmed alt attribute value.
+	Alt string
+	// HasAlt reports whether the alt attribute is present at all.
+	HasAlt bool
+	// Width and Height are the raw width/height attribute values.
+	Width  string
+	Height string
+	// InMainContent reports whether the image sits inside <main>, <article>
+	// or an element with role="main".
+	InMainContent bool
+}
+
+// MaxSitemapImages is the maximum number of <image:image> entries allowed
+// per sitemap <url>.
+const MaxSitemapImages = 1000
+
+// mainContentSelector matches the elements treated as a page's main content.
+const mainContentSelector = `main, article, [role="main"]`
+
+// Metadata describes an image as observed when fetching it.
+type Metadata struct {
+	// Status is the HTTP status code (0 = request failed).
+	Status int
+	// ContentType is the response Content-Type header.
+	ContentType string
+	// Size is the image size in bytes, or -1 when it could not be determined.
+	Size int64
+	// Error holds the request error message when the fetch failed.
+	Error string
+}
+
+// Audit aggregates every occurrence of one image across the crawl.
+type Audit struct {
+	URL string
+	// Pages lists every page embedding the image.
+	Pages []string
+	// MissingAlt lists pages where the <img> has no alt attribute.
+	MissingAlt []string
+	// EmptyAlt lists pages where the alt attribute is present but empty,
+	// which marks a decorative image. It is informational and not an issue.
+	EmptyAlt []string
+	// MissingDimensions lists pages where width or height is not declared,
+	// which causes layout shift while the image loads.
+	MissingDimensions []string
+	Status            int
+	ContentType       string
+	// Size is the image size in bytes, or -1 when unknown.
+	Size  int64
+	Error string
+	// Oversize is set when Size exceeds the configured byte budget.
+	Oversize bool
+}
+
+// HasIssues reports whether the audit contains any finding worth reporting.
+// An empty alt attribute alone is not one.
+func (a Audit) HasIssues() bool {
+	return len(a.MissingAlt) > 0 ||
+		len(a.MissingDimensions) > 0 ||
+		a.Oversize ||
+		a.Error != "" ||
+		a.Status == 0 ||
+		a.Status >= 400
+}
+
+// Extract returns every <img> with a resolvable HTTP(S) src on the page.
+// Data URIs and empty src values are skipped.
+func Extract(pageURL string, doc *goquery.Document) []Image {
+	if doc == nil {
+		return nil
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	found := make([]Image, 0)
+	doc.Find("img[src]").Each(func(_ int, s *goquery.Selection) {
+		src := strings.TrimSpace(s.AttrOr("src", ""))
+		if src == "" || strings.HasPrefix(strings.ToLower(src), "data:") {
+			return
+		}
+
+		parsed, err := url.Parse(src)
+		if err != nil {
+			return
+		}
+		resolved := base.ResolveReference(parsed)
+		if resolved.Scheme != "http" && resolved.Scheme != "https" {
+			return
+		}
+		resolved.Fragment = ""
+
+		alt, hasAlt := s.Attr("alt")
+		found = append(found, Image{
+			URL:           resolved.String(),
+			Alt:           strings.TrimSpace(alt),
+			HasAlt:        hasAlt,
+			Width:         strings.TrimSpace(s.AttrOr("width", "")),
+			Height:        strings.TrimSpace(s.AttrOr("height", "")),
+			InMainContent: s.ParentsFiltered(mainContentSelector).Length() > 0,
+		})
+	})
+
+	return found
+}
+
+// BuildAudits aggregates page images into one Audit per image URL. meta holds
+// fetched image metadata keyed by image URL and may be nil. Images larger
+// than maxBytes are flagged as oversize; a maxBytes of 0 disables the check.
+func BuildAudits(imagesByPage map[string][]Image, meta map[string]Metadata, maxBytes int64) []Audit {
+	byURL := make(map[string]*auditSets)
+	for page, pageImages := range imagesByPage {
+		for _, img := range pageImages {
+			sets, ok := byURL[img.URL]
+			if !ok {
+				sets = newAuditSets()
+				byURL[img.URL] = sets
+			}
+
+			sets.pages[page] = struct{}{}
+			if !img.HasAlt {
+				sets.missingAlt[page] = struct{}{}
+			} else if img.Alt == "" {
+				sets.emptyAlt[page] = struct{}{}
+			}
+			if img.Width == "" || img.Height == "" {
+				sets.missingDimensions[page] = struct{}{}
+			}
+		}
+	}
+
+	audits := make([]Audit, 0, len(byURL))
+	for imageURL, sets := range byURL {
+		audit := Audit{
+			URL:               imageURL,
+			Pages:             sortedKeys(sets.pages),
+			MissingAlt:        sortedKeys(sets.missingAlt),
+			EmptyAlt:          sortedKeys(sets.emptyAlt),
+			MissingDimensions: sortedKeys(sets.missingDimensions),
+			Size:              -1,
+		}
+		if m, ok := meta[imageURL]; ok {
+			audit.Status = m.Status
+			audit.ContentType = m.ContentType
+			audit.Size = m.Size
+			audit.Error = m.Error
+		}
+		if maxBytes > 0 && audit.Size > maxBytes {
+			audit.Oversize = true
+		}
+		audits = append(audits, audit)
+	}
+
+	sort.Slice(audits, func(i, j int) bool {
+		return audits[i].URL < audits[j].URL
+	})
+
+	return audits
+}
+
+// SitemapImages selects, for every page, the image URLs to list in the image
+// sitemap extension. Only images on the page's own host or on one of
+// allowedHosts are kept; when mainOnly is set, images outside the main
+// content are dropped as well. Each page is capped at MaxSitemapImages unique
+// images, in document order.
+func SitemapImages(imagesByPage map[string][]Image, allowedHosts []string, mainOnly bool) map[string][]string {
+	allowed := make(map[string]struct{}, len(allowedHosts))
+	for _, host := range allowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			allowed[host] = struct{}{}
+		}
+	}
+
+	selected := make(map[string][]string)
+	for page, pageImages := range imagesByPage {
+		parsedPage, err := url.Parse(page)
+		if err != nil {
+			continue
+		}
+		pageHost := strings.ToLower(parsedPage.Hostname())
+
+		seen := make(map[string]struct{})
+		list := make([]string, 0)
+		for _, img := range pageImages {
+			if len(list) >= MaxSitemapImages {
+				break
+			}
+			if mainOnly && !img.InMainContent {
+				continue
+			}
+			if _, dup := seen[img.URL]; dup {
+				continue
+			}
+
+			parsedImage, err := url.Parse(img.URL)
+			if err != nil {
+				continue
+			}
+			imageHost := strings.ToLower(parsedImage.Hostname())
+			if _, ok := allowed[imageHost]; imageHost != pageHost && !ok {
+				continue
+			}
+
+			seen[img.URL] = struct{}{}
+			list = append(list, img.URL)
+		}
+
+		if len(list) > 0 {
+			selected[page] = list
+		}
+	}
+
+	return selected
+}
+
+type auditSets struct {
+	pages             map[string]struct{}
+	missingAlt        map[string]struct{}
+	emptyAlt          map[string]struct{}
+	missingDimensions map[string]struct{}
+}
+
+func newAuditSets() *auditSets {
+	return &auditSets{
+		pages:             make(map[string]struct{}),
+		missingAlt:        make(map[string]struct{}),
+		emptyAlt:          make(map[string]struct{}),
+		missingDimensions: make(map[string]struct{}),
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,174 @@
+package images
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func docFromHTML(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("build document: %v", err)
+	}
+	return doc
+}
+
+func TestExtract_ResolvesAndSkipsDataURIs(t *testing.T) {
+	doc := docFromHTML(t, `<html><body>
+		<img src="/img/logo.png" alt="Logo" width="100" height="50">
+		<img src="https://cdn.example.net/hero.jpg#x">
+		<img src="data:image/png;base64,AAAA" alt="">
+		<img src="">
+	</body></html>`)
+
+	got := Extract("https://example.com/products/shoes", doc)
+	if len(got) != 2 {
+		t.Fatalf("Extract returned %d images, want 2: %+v", len(got), got)
+	}
+
+	if got[0].URL != "https://example.com/img/logo.png" {
+		t.Errorf("URL=%q, want resolved logo URL", got[0].URL)
+	}
+	if !got[0].HasAlt || got[0].Alt != "Logo" || got[0].Width != "100" || got[0].Height != "50" {
+		t.Errorf("unexpected attributes: %+v", got[0])
+	}
+	if got[1].URL != "https://cdn.example.net/hero.jpg" {
+		t.Errorf("URL=%q, want fragment stripped", got[1].URL)
+	}
+	if got[1].HasAlt {
+		t.Error("expected HasAlt=false for img without alt")
+	}
+}
+
+func TestExtract_NilDocument(t *testing.T) {
+	if got := Extract("https://example.com/", nil); got != nil {
+		t.Fatalf("Extract(nil)=%v, want nil", got)
+	}
+}
+
+func TestBuildAudits(t *testing.T) {
+	imagesByPage := map[string][]Image{
+		"https://example.com/a": {
+			{URL: "https://example.com/hero.jpg", HasAlt: false, Width: "800", Height: "600"},
+			{URL: "https://example.com/logo.png", HasAlt: true, Alt: "Logo", Width: "100", Height: "50"},
+		},
+		"https://example.com/b": {
+			{URL: "https://example.com/hero.jpg", HasAlt: true, Alt: ""},
+		},
+	}
+	meta := map[string]Metadata{
+		"https://example.com/hero.jpg": {Status: 200, ContentType: "image/jpeg", Size: 500000},
+		"https://example.com/logo.png": {Status: 200, ContentType: "image/png", Size: 2000},
+	}
+
+	audits := BuildAudits(imagesByPage, meta, 100000)
+	if len(audits) != 2 {
+		t.Fatalf("got %d audits, want 2", len(audits))
+	}
+
+	hero := audits[0]
+	if hero.URL != "https://example.com/hero.jpg" {
+		t.Fatalf("audits not sorted by URL: %q", hero.URL)
+	}
+	if len(hero.Pages) != 2 {
+		t.Errorf("hero Pages=%v, want both pages", hero.Pages)
+	}
+	if len(hero.MissingAlt) != 1 || hero.MissingAlt[0] != "https://example.com/a" {
+		t.Errorf("hero MissingAlt=%v", hero.MissingAlt)
+	}
+	if len(hero.EmptyAlt) != 1 || hero.EmptyAlt[0] != "https://example.com/b" {
+		t.Errorf("hero EmptyAlt=%v", hero.EmptyAlt)
+	}
+	if len(hero.MissingDimensions) != 1 || hero.MissingDimensions[0] != "https://example.com/b" {
+		t.Errorf("hero MissingDimensions=%v", hero.MissingDimensions)
+	}
+	if !hero.Oversize {
+		t.Error("expected hero to be oversize")
+	}
+	if !hero.HasIssues() {
+		t.Error("expected hero to have issues")
+	}
+
+	logo := audits[1]
+	if logo.Oversize || logo.HasIssues() {
+		t.Errorf("logo should have no issues: %+v", logo)
+	}
+
+	decorative := Audit{URL: "https://example.com/divider.png", EmptyAlt: []string{"https://example.com/a"}, Status: 200, Size: 100}
+	if decorative.HasIssues() {
+		t.Errorf("an empty alt marks a decorative image and is not an issue: %+v", decorative)
+	}
+}
+
+func TestBuildAudits_NoBudget(t *testing.T) {
+	imagesByPage := map[string][]Image{
+		"https://example.com/": {{URL: "https://example.com/big.jpg", HasAlt: true, Alt: "Big", Width: "1", Height: "1"}},
+	}
+	meta := map[string]Metadata{
+		"https://example.com/big.jpg": {Status: 200, Size: 10 << 20},
+	}
+
+	audits := BuildAudits(imagesByPage, meta, 0)
+	if audits[0].Oversize {
+		t.Error("maxBytes=0 should disable the oversize check")
+	}
+}
+
+func TestExtract_MainContent(t *testing.T) {
+	doc := docFromHTML(t, `<html><body>
+		<header><img src="/logo.png"></header>
+		<main><div><img src="/product.jpg"></div></main>
+		<div role="main"><img src="/detail.jpg"></div>
+	</body></html>`)
+
+	got := Extract("https://example.com/", doc)
+	if len(got) != 3 {
+		t.Fatalf("Extract returned %d images, want 3", len(got))
+	}
+	if got[0].InMainContent {
+		t.Error("header image should not be main content")
+	}
+	if !got[1].InMainContent || !got[2].InMainContent {
+		t.Error("images in <main> and role=main should be main content")
+	}
+}
+
+func TestSitemapImages_FiltersHostsAndMainContent(t *testing.T) {
+	imagesByPage := map[string][]Image{
+		"https://example.com/p": {
+			{URL: "https://example.com/a.jpg", InMainContent: true},
+			{URL: "https://cdn.example.net/b.jpg", InMainContent: true},
+			{URL: "https://tracker.example.org/pixel.gif", InMainContent: true},
+			{URL: "https://example.com/logo.png"},
+			{URL: "https://example.com/a.jpg", InMainContent: true},
+		},
+	}
+
+	got := SitemapImages(imagesByPage, []string{"CDN.example.net"}, false)
+	want := []string{"https://example.com/a.jpg", "https://cdn.example.net/b.jpg", "https://example.com/logo.png"}
+	if strings.Join(got["https://example.com/p"], ",") != strings.Join(want, ",") {
+		t.Errorf("SitemapImages = %v, want %v", got["https://example.com/p"], want)
+	}
+
+	mainOnly := SitemapImages(imagesByPage, []string{"cdn.example.net"}, true)
+	wantMain := []string{"https://example.com/a.jpg", "https://cdn.example.net/b.jpg"}
+	if strings.Join(mainOnly["https://example.com/p"], ",") != strings.Join(wantMain, ",") {
+		t.Errorf("SitemapImages(mainOnly) = %v, want %v", mainOnly["https://example.com/p"], wantMain)
+	}
+}
+
+func TestSitemapImages_CapsPerPage(t *testing.T) {
+	pageImages := make([]Image, 0, MaxSitemapImages+10)
+	for i := 0; i < MaxSitemapImages+10; i++ {
+		pageImages = append(pageImages, Image{URL: fmt.Sprintf("https://example.com/img/%d.jpg", i)})
+	}
+
+	got := SitemapImages(map[string][]Image{"https://example.com/": pageImages}, nil, false)
+	if len(got["https://example.com/"]) != MaxSitemapImages {
+		t.Fatalf("got %d images, want cap of %d", len(got["https://example.com/"]), MaxSitemapImages)
+	}
+}
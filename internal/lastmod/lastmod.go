@@ -1,15 +1,27 @@
 // Package lastmod extracts the most trustworthy "last modified" timestamp
-// from an HTTP response using a priority-based extraction hierarchy:
+// from an HTTP response using a priority-based extraction hierarchy. The
+// default order is:
 //
 //  1. JSON-LD structured data (dateModified)
-//  2. HTML meta tags (article:modified_time, og:updated_time)
+//  2. HTML meta tags (article:modified_time, og:updated_time, last-modified)
 //  3. HTTP Last-Modified header
-//  4. Fallback: current crawl time
+//  4. Microdata (itemprop="dateModified")
+//  5. Dublin Core meta tags (DC.date.modified, dcterms.modified)
+//  6. <time datetime> inside <article>
+//  7. An existing sitemap's <lastmod>
+//  8. Fallback: current crawl time
+//
+// Options can enable a subset of sources in a different order. A Store can
+// replace the fallback with the time the page's content last changed across
+// crawls.
 package lastmod
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,95 +30,255 @@ import (
 
 // knownFormats lists the date/time layouts we try when parsing timestamps
 // found in HTML or HTTP headers. They are attempted in order.
+// Fractional seconds are accepted after the seconds field of any layout.
 var knownFormats = []string{
-	time.RFC3339,           // 2006-01-02T15:04:05Z07:00
-	"2006-01-02T15:04:05Z", // UTC explicit
-	"2006-01-02T15:04:05",  // no tz
-	"2006-01-02",           // date only (W3C short)
-	time.RFC1123,           // Mon, 02 Jan 2006 15:04:05 MST
-	time.RFC1123Z,          // Mon, 02 Jan 2006 15:04:05 -0700
-	time.RFC850,            // Monday, 02-Jan-06 15:04:05 MST
+	time.RFC3339,               // 2006-01-02T15:04:05Z07:00
+	"2006-01-02T15:04:05Z",     // UTC explicit
+	"2006-01-02T15:04:05-0700", // numeric offset without colon
+	"2006-01-02T15:04Z07:00",   // W3C minute precision
+	"2006-01-02T15:04-0700",    // minute precision, offset without colon
+	"2006-01-02T15:04:05",      // no tz
+	"2006-01-02T15:04",         // minute precision, no tz
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05-0700",
+	"2006-01-02 15:04:05", // SQL style, no tz
+	"2006-01-02",          // date only (W3C short)
+	time.RFC1123,          // Mon, 02 Jan 2006 15:04:05 MST
+	time.RFC1123Z,         // Mon, 02 Jan 2006 15:04:05 -0700
+	time.RFC850,           // Monday, 02-Jan-06 15:04:05 MST
 	"Mon, 2 Jan 2006 15:04:05 MST",
 }
 
+// isoWeekPattern matches ISO 8601 week dates such as 2025-W03-2, 2025W032
+// and 2025-W03 (Monday of the week).
+var isoWeekPattern = regexp.MustCompile(`^(\d{4})-?W(\d{2})(?:-?([1-7]))?$`)
+
+// Precision is the granularity of a formatted W3C datetime.
+type Precision string
+
+const (
+	// PrecisionDate formats YYYY-MM-DD.
+	PrecisionDate Precision = "date"
+	// PrecisionMinute formats YYYY-MM-DDThh:mmTZD.
+	PrecisionMinute Precision = "minute"
+	// PrecisionSecond formats YYYY-MM-DDThh:mm:ssTZD.
+	PrecisionSecond Precision = "second"
+)
+
+// ParsePrecision converts "date", "minute" or "second" into a Precision.
+func ParsePrecision(name string) (Precision, error) {
+	switch p := Precision(strings.ToLower(strings.TrimSpace(name))); p {
+	case PrecisionDate, PrecisionMinute, PrecisionSecond:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown lastmod precision %q (want date, minute or second)", name)
+}
+
+// Source identifies where a lastmod value came from.
+type Source string
+
+const (
+	SourceJSONLD     Source = "jsonld"
+	SourceMicrodata  Source = "microdata"
+	SourceMeta       Source = "meta"
+	SourceDublinCore Source = "dublin_core"
+	SourceTime       Source = "time"
+	SourceHeader     Source = "header"
+	// SourceSitemap reads the <lastmod> of an existing sitemap supplied via
+	// Options.SitemapLastmod.
+	SourceSitemap Source = "sitemap"
+	// SourceHistory marks a lastmod kept from a previous crawl by a Store
+	// because the page content did not change.
+	SourceHistory Source = "history"
+	// SourceFallback marks the crawl time used when no source was found.
+	SourceFallback Source = "fallback"
+)
+
+// DefaultSources is the extraction order used when Options.Sources is empty.
+// The original JSON-LD, meta and header order comes first so that adding
+// sources does not change the lastmod of pages that already had one.
+var DefaultSources = []Source{
+	SourceJSONLD,
+	SourceMeta,
+	SourceHeader,
+	SourceMicrodata,
+	SourceDublinCore,
+	SourceTime,
+	SourceSitemap,
+}
+
+// ParseSource converts a source name such as "header" into a Source. Only
+// sources usable in Options.Sources are accepted.
+func ParseSource(name string) (Source, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, source := range DefaultSources {
+		if string(source) == name {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("unknown lastmod source %q", name)
+}
+
+// Options configures lastmod extraction.
+type Options struct {
+	// Sources lists the enabled sources in priority order. Empty uses
+	// DefaultSources.
+	Sources []Source
+	// SitemapLastmod maps page URLs to the <lastmod> of an existing sitemap
+	// and feeds SourceSitemap.
+	SitemapLastmod map[string]time.Time
+	// Location is the time zone assumed for values without an offset. Nil
+	// means UTC.
+	Location *time.Location
+}
+
+// Candidate is a single modification date found on a page.
+type Candidate struct {
+	Source Source
+	// Field names the property, tag or header the date was read from, e.g.
+	// "dateModified", "article:modified_time" or "Last-Modified".
+	Field string
+	Time  time.Time
+}
+
+// Info is the lastmod chosen for a page together with its provenance and
+// every candidate date that was found.
+type Info struct {
+	Time   time.Time
+	Source Source
+	// Candidates lists every parsable date in priority order; the first one
+	// is the chosen value unless Source is SourceFallback or SourceHistory.
+	Candidates []Candidate
+	// ChangeInterval is the mean time between content changes recorded by a
+	// Store; zero when there is no history.
+	ChangeInterval time.Duration
+}
+
+// Times flattens a map of Info values into the chosen times per URL.
+func Times(infos map[string]Info) map[string]time.Time {
+	times := make(map[string]time.Time, len(infos))
+	for u, info := range infos {
+		times[u] = info.Time
+	}
+	return times
+}
+
 // GetLastModified returns the best available "last modified" time for a page.
-// It inspects (in priority order): JSON-LD dateModified, HTML meta tags,
-// the HTTP Last-Modified header, and finally falls back to time.Now().
+// It inspects DefaultSources in priority order and falls back to now when
+// none of them declares a date.
 //
 // The returned time is always in UTC.
 func GetLastModified(header http.Header, doc *goquery.Document, now time.Time) time.Time {
-	if doc != nil {
-		// 1. JSON-LD structured data.
-		if t, ok := fromJSONLD(doc); ok {
-			return t.UTC()
-		}
+	return Extract(header, doc, now).Time
+}
 
-		// 2. HTML meta tags.
-		if t, ok := fromMetaTags(doc); ok {
-			return t.UTC()
-		}
+// Extract collects every modification date a page declares using
+// DefaultSources and picks the highest-priority one, falling back to now when
+// none is found. All times are in UTC.
+func Extract(header http.Header, doc *goquery.Document, now time.Time) Info {
+	return ExtractWithOptions("", header, doc, now, Options{})
+}
+
+// ExtractWithOptions is like Extract but uses the sources and order from
+// opts. pageURL is only needed for SourceSitemap.
+func ExtractWithOptions(pageURL string, header http.Header, doc *goquery.Document, now time.Time, opts Options) Info {
+	sources := opts.Sources
+	if len(sources) == 0 {
+		sources = DefaultSources
 	}
 
-	// 3. HTTP Last-Modified header.
-	if header != nil {
-		if t, ok := fromHeader(header); ok {
-			return t.UTC()
-		}
+	candidates := make([]Candidate, 0)
+	for _, source := range sources {
+		candidates = append(candidates, candidatesFrom(source, pageURL, header, doc, opts)...)
 	}
+	if len(candidates) == 0 {
+		return Info{Time: now.UTC(), Source: SourceFallback, Candidates: candidates}
+	}
+	return Info{Time: candidates[0].Time, Source: candidates[0].Source, Candidates: candidates}
+}
 
-	// 4. Fallback.
-	return now.UTC()
+// candidatesFrom returns the dates found by a single source.
+func candidatesFrom(source Source, pageURL string, header http.Header, doc *goquery.Document, opts Options) []Candidate {
+	candidates := make([]Candidate, 0)
+	switch source {
+	case SourceJSONLD:
+		if doc != nil {
+			for _, t := range fromJSONLD(doc, opts.Location) {
+				candidates = append(candidates, Candidate{Source: SourceJSONLD, Field: "dateModified", Time: t.UTC()})
+			}
+		}
+	case SourceMicrodata:
+		if doc != nil {
+			candidates = append(candidates, fromMicrodata(doc, opts.Location)...)
+		}
+	case SourceMeta:
+		if doc != nil {
+			candidates = append(candidates, fromMetaTags(doc, opts.Location)...)
+		}
+	case SourceDublinCore:
+		if doc != nil {
+			candidates = append(candidates, fromMetaNames(doc, opts.Location, SourceDublinCore, "dc.date.modified", "dcterms.modified")...)
+		}
+	case SourceTime:
+		if doc != nil {
+			if t, ok := fromArticleTime(doc, opts.Location); ok {
+				candidates = append(candidates, Candidate{Source: SourceTime, Field: "article time", Time: t.UTC()})
+			}
+		}
+	case SourceHeader:
+		if header != nil {
+			if t, ok := fromHeader(header); ok {
+				candidates = append(candidates, Candidate{Source: SourceHeader, Field: "Last-Modified", Time: t.UTC()})
+			}
+		}
+	case SourceSitemap:
+		if t, ok := opts.SitemapLastmod[pageURL]; ok && !t.IsZero() {
+			candidates = append(candidates, Candidate{Source: SourceSitemap, Field: "lastmod", Time: t.UTC()})
+		}
+	}
+	return candidates
 }
 
-// fromJSONLD scans all <script type="application/ld+json"> blocks for a
-// "dateModified" key. If the JSON is an array of objects, each element is
+// fromJSONLD scans all <script type="application/ld+json"> blocks for
+// "dateModified" keys. If the JSON is an array of objects, each element is
 // checked.
-func fromJSONLD(doc *goquery.Document) (time.Time, bool) {
-	var result time.Time
-	var found bool
+func fromJSONLD(doc *goquery.Document, loc *time.Location) []time.Time {
+	found := make([]time.Time, 0)
 
-	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
 		raw := strings.TrimSpace(s.Text())
 		if raw == "" {
-			return true // continue
+			return
 		}
 
 		// Try single object first.
 		var obj map[string]interface{}
 		if err := json.Unmarshal([]byte(raw), &obj); err == nil {
-			if t, ok := extractDateModified(obj); ok {
-				result = t
-				found = true
-				return false // break
-			}
-			return true
+			found = append(found, extractDateModified(obj, loc)...)
+			return
 		}
 
 		// Try array of objects.
 		var arr []map[string]interface{}
 		if err := json.Unmarshal([]byte(raw), &arr); err == nil {
 			for _, item := range arr {
-				if t, ok := extractDateModified(item); ok {
-					result = t
-					found = true
-					return false
-				}
+				found = append(found, extractDateModified(item, loc)...)
 			}
 		}
-
-		return true
 	})
 
-	return result, found
+	return found
 }
 
-// extractDateModified looks for "dateModified" in a JSON-LD object,
+// extractDateModified collects "dateModified" values from a JSON-LD object,
 // including inside a nested "@graph" array.
-func extractDateModified(obj map[string]interface{}) (time.Time, bool) {
+func extractDateModified(obj map[string]interface{}, loc *time.Location) []time.Time {
+	found := make([]time.Time, 0)
 	if val, ok := obj["dateModified"]; ok {
 		if s, ok := val.(string); ok {
-			if t, ok := parseTime(s); ok {
-				return t, true
+			if t, ok := parseTimeIn(s, loc); ok {
+				found = append(found, t)
 			}
 		}
 	}
@@ -116,35 +288,85 @@ func extractDateModified(obj map[string]interface{}) (time.Time, bool) {
 		if items, ok := graph.([]interface{}); ok {
 			for _, item := range items {
 				if m, ok := item.(map[string]interface{}); ok {
-					if t, ok := extractDateModified(m); ok {
-						return t, true
-					}
+					found = append(found, extractDateModified(m, loc)...)
 				}
 			}
 		}
 	}
 
-	return time.Time{}, false
+	return found
 }
 
-// fromMetaTags checks <meta> tags for article:modified_time and
-// og:updated_time (in that order).
-func fromMetaTags(doc *goquery.Document) (time.Time, bool) {
-	selectors := []string{
-		`meta[property="article:modified_time"]`,
-		`meta[property="og:updated_time"]`,
+// fromMetaTags checks <meta> tags for article:modified_time,
+// og:updated_time and name="last-modified" (in that order).
+func fromMetaTags(doc *goquery.Document, loc *time.Location) []Candidate {
+	properties := []string{
+		"article:modified_time",
+		"og:updated_time",
 	}
 
-	for _, sel := range selectors {
-		if val, exists := doc.Find(sel).First().Attr("content"); exists {
-			val = strings.TrimSpace(val)
-			if t, ok := parseTime(val); ok {
-				return t, true
+	found := make([]Candidate, 0)
+	for _, property := range properties {
+		if val, exists := doc.Find(`meta[property="` + property + `"]`).First().Attr("content"); exists {
+			if t, ok := parseTimeIn(val, loc); ok {
+				found = append(found, Candidate{Source: SourceMeta, Field: property, Time: t.UTC()})
 			}
 		}
 	}
 
-	return time.Time{}, false
+	return append(found, fromMetaNames(doc, loc, SourceMeta, "last-modified")...)
+}
+
+// fromMetaNames checks <meta name content> tags whose name matches one of
+// names case-insensitively, in the order given.
+func fromMetaNames(doc *goquery.Document, loc *time.Location, source Source, names ...string) []Candidate {
+	found := make([]Candidate, 0)
+	for _, name := range names {
+		doc.Find("meta[name]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			if !strings.EqualFold(strings.TrimSpace(s.AttrOr("name", "")), name) {
+				return true
+			}
+			if t, ok := parseTimeIn(s.AttrOr("content", ""), loc); ok {
+				found = append(found, Candidate{Source: source, Field: s.AttrOr("name", ""), Time: t.UTC()})
+				return false
+			}
+			return true
+		})
+	}
+	return found
+}
+
+// fromMicrodata reads the first itemprop="dateModified" element, taking the
+// value from its content or datetime attribute, or its text.
+func fromMicrodata(doc *goquery.Document, loc *time.Location) []Candidate {
+	found := make([]Candidate, 0)
+	doc.Find(`[itemprop~="dateModified"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		raw, ok := s.Attr("content")
+		if !ok {
+			raw, ok = s.Attr("datetime")
+		}
+		if !ok {
+			raw = s.Text()
+		}
+		if t, ok := parseTimeIn(raw, loc); ok {
+			found = append(found, Candidate{Source: SourceMicrodata, Field: "itemprop=dateModified", Time: t.UTC()})
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// fromArticleTime returns the latest <time datetime> inside an <article>, as
+// articles typically show both a published and an updated date.
+func fromArticleTime(doc *goquery.Document, loc *time.Location) (time.Time, bool) {
+	var latest time.Time
+	doc.Find("article time[datetime]").Each(func(_ int, s *goquery.Selection) {
+		if t, ok := parseTimeIn(s.AttrOr("datetime", ""), loc); ok && t.After(latest) {
+			latest = t
+		}
+	})
+	return latest, !latest.IsZero()
 }
 
 // fromHeader parses the HTTP Last-Modified header.
@@ -156,21 +378,90 @@ func fromHeader(header http.Header) (time.Time, bool) {
 	return parseTime(raw)
 }
 
+// ParseTime parses a date string found in HTML, JSON-LD or HTTP headers using
+// the same formats as the lastmod extraction hierarchy. Values without an
+// offset are read as UTC.
+func ParseTime(raw string) (time.Time, bool) {
+	return parseTime(raw)
+}
+
+// ParseTimeIn is like ParseTime but reads values without an offset in loc.
+func ParseTimeIn(raw string, loc *time.Location) (time.Time, bool) {
+	return parseTimeIn(raw, loc)
+}
+
 // parseTime attempts to parse a date string against all known formats.
 func parseTime(raw string) (time.Time, bool) {
+	return parseTimeIn(raw, time.UTC)
+}
+
+// parseTimeIn attempts to parse a date string against all known formats and
+// ISO week dates, interpreting zone-less values in loc (UTC when nil).
+func parseTimeIn(raw string, loc *time.Location) (time.Time, bool) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
 		return time.Time{}, false
 	}
+	if loc == nil {
+		loc = time.UTC
+	}
 	for _, layout := range knownFormats {
-		if t, err := time.Parse(layout, raw); err == nil {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
 			return t, true
 		}
 	}
-	return time.Time{}, false
+	return parseISOWeek(raw, loc)
+}
+
+// parseISOWeek parses an ISO 8601 week date into midnight of that day.
+func parseISOWeek(raw string, loc *time.Location) (time.Time, bool) {
+	m := isoWeekPattern.FindStringSubmatch(raw)
+	if m == nil {
+		return time.Time{}, false
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	weekday := 1
+	if m[3] != "" {
+		weekday, _ = strconv.Atoi(m[3])
+	}
+	if week < 1 || week > 53 {
+		return time.Time{}, false
+	}
+
+	// Week 1 is the week containing January 4th; weeks start on Monday.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	week1Monday := jan4.AddDate(0, 0, -offset)
+	t := week1Monday.AddDate(0, 0, (week-1)*7+weekday-1)
+
+	if week == 53 {
+		if _, w := t.ISOWeek(); w != 53 {
+			return time.Time{}, false
+		}
+	}
+	return t, true
 }
 
 // FormatW3C formats a time as a W3C Datetime / ISO 8601 date (YYYY-MM-DD).
 func FormatW3C(t time.Time) string {
 	return t.UTC().Format("2006-01-02")
 }
+
+// FormatW3CPrecision formats t as a W3C Datetime at the given precision in
+// loc (UTC when nil), e.g. 2025-06-15T10:30:00+02:00. An empty precision
+// formats a date.
+func FormatW3CPrecision(t time.Time, precision Precision, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	switch precision {
+	case PrecisionMinute:
+		return t.Format("2006-01-02T15:04Z07:00")
+	case PrecisionSecond:
+		return t.Format("2006-01-02T15:04:05Z07:00")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
@@ -225,6 +225,207 @@ func TestPriority_MetaWinsOverHeader(t *testing.T) {
 
 // --- FormatW3C tests ---
 
+func TestExtract_SourceAndCandidates(t *testing.T) {
+	html := `<html><head>
+	<script type="application/ld+json">{"dateModified":"2025-06-15T10:30:00Z"}</script>
+	<meta property="og:updated_time" content="2025-06-14T09:00:00Z">
+	</head><body></body></html>`
+	h := http.Header{}
+	h.Set("Last-Modified", "Mon, 02 Jan 2023 10:00:00 GMT")
+
+	info := Extract(h, docFromHTML(html), fixedNow)
+	if info.Source != SourceJSONLD {
+		t.Fatalf("Source=%q, want jsonld", info.Source)
+	}
+	if len(info.Candidates) != 3 {
+		t.Fatalf("Candidates=%+v, want 3", info.Candidates)
+	}
+	want := []struct {
+		source Source
+		field  string
+	}{
+		{SourceJSONLD, "dateModified"},
+		{SourceMeta, "og:updated_time"},
+		{SourceHeader, "Last-Modified"},
+	}
+	for i, w := range want {
+		if info.Candidates[i].Source != w.source || info.Candidates[i].Field != w.field {
+			t.Errorf("candidate %d = %+v, want %s %s", i, info.Candidates[i], w.source, w.field)
+		}
+	}
+}
+
+func TestExtract_Fallback(t *testing.T) {
+	info := Extract(nil, docFromHTML(`<html><body></body></html>`), fixedNow)
+	if info.Source != SourceFallback || !info.Time.Equal(fixedNow) || len(info.Candidates) != 0 {
+		t.Fatalf("unexpected fallback info: %+v", info)
+	}
+}
+
+func TestMicrodata_DateModified(t *testing.T) {
+	html := `<html><body><div itemscope itemtype="https://schema.org/Article">
+	<meta itemprop="dateModified" content="2025-03-04T05:06:07Z">
+	</div></body></html>`
+
+	info := Extract(nil, docFromHTML(html), fixedNow)
+	want := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	if info.Source != SourceMicrodata || !info.Time.Equal(want) {
+		t.Errorf("microdata: got %+v, want %v", info, want)
+	}
+}
+
+func TestMetaName_LastModified(t *testing.T) {
+	html := `<html><head><meta name="Last-Modified" content="2025-02-01"></head></html>`
+
+	info := Extract(nil, docFromHTML(html), fixedNow)
+	if info.Source != SourceMeta || info.Candidates[0].Field != "Last-Modified" {
+		t.Errorf("meta last-modified: got %+v", info)
+	}
+}
+
+func TestDublinCore(t *testing.T) {
+	html := `<html><head><meta name="DC.date.modified" content="2024-11-30"></head></html>`
+
+	info := Extract(nil, docFromHTML(html), fixedNow)
+	want := time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	if info.Source != SourceDublinCore || !info.Time.Equal(want) {
+		t.Errorf("dublin core: got %+v, want %v", info, want)
+	}
+}
+
+func TestArticleTime_Latest(t *testing.T) {
+	html := `<html><body>
+	<time datetime="2020-01-01">outside article</time>
+	<article>
+		<time datetime="2024-05-01">Published</time>
+		<time datetime="2024-06-10">Updated</time>
+	</article></body></html>`
+
+	info := Extract(nil, docFromHTML(html), fixedNow)
+	want := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	if info.Source != SourceTime || !info.Time.Equal(want) {
+		t.Errorf("article time: got %+v, want %v", info, want)
+	}
+}
+
+func TestPriority_HeaderWinsOverNewSources(t *testing.T) {
+	html := `<html><head><meta name="DC.date.modified" content="2025-03-01"></head><body>
+	<div itemscope><meta itemprop="dateModified" content="2025-03-02"></div>
+	<article><time datetime="2025-03-03">Updated</time></article>
+	</body></html>`
+	h := http.Header{}
+	h.Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+
+	info := Extract(h, docFromHTML(html), fixedNow)
+	want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if info.Source != SourceHeader || !info.Time.Equal(want) {
+		t.Errorf("header over microdata, Dublin Core and time: got %+v, want %v", info, want)
+	}
+}
+
+func TestExtractWithOptions_CustomOrder(t *testing.T) {
+	html := `<html><head>
+	<script type="application/ld+json">{"dateModified":"2020-01-01"}</script>
+	</head><body></body></html>`
+	h := http.Header{}
+	h.Set("Last-Modified", "Mon, 02 Jan 2025 10:00:00 GMT")
+
+	info := ExtractWithOptions("", h, docFromHTML(html), fixedNow, Options{Sources: []Source{SourceHeader, SourceJSONLD}})
+	if info.Source != SourceHeader || len(info.Candidates) != 2 {
+		t.Fatalf("custom order: got %+v", info)
+	}
+
+	info = ExtractWithOptions("", h, docFromHTML(html), fixedNow, Options{Sources: []Source{SourceMeta}})
+	if info.Source != SourceFallback || len(info.Candidates) != 0 {
+		t.Fatalf("disabled sources should be ignored: got %+v", info)
+	}
+}
+
+func TestExtractWithOptions_Sitemap(t *testing.T) {
+	want := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	opts := Options{SitemapLastmod: map[string]time.Time{"https://example.com/a": want}}
+
+	info := ExtractWithOptions("https://example.com/a", nil, docFromHTML(`<html></html>`), fixedNow, opts)
+	if info.Source != SourceSitemap || !info.Time.Equal(want) {
+		t.Errorf("sitemap source: got %+v, want %v", info, want)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	if got, err := ParseSource(" Header "); err != nil || got != SourceHeader {
+		t.Errorf("ParseSource(header) = %q, %v", got, err)
+	}
+	for _, name := range []string{"fallback", "history", "bogus"} {
+		if _, err := ParseSource(name); err == nil {
+			t.Errorf("ParseSource(%q) should fail", name)
+		}
+	}
+}
+
+func TestParseTimeIn_ZonelessValues(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*3600)
+
+	got, ok := ParseTimeIn("2025-06-15T10:30:00", berlin)
+	if !ok || !got.Equal(time.Date(2025, 6, 15, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("zone-less value = %v, want 08:30 UTC", got)
+	}
+
+	got, ok = ParseTimeIn("2025-06-15T10:30:00Z", berlin)
+	if !ok || !got.Equal(time.Date(2025, 6, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("explicit offset should win over location, got %v", got)
+	}
+}
+
+func TestParseTime_InvalidISOWeek(t *testing.T) {
+	for _, input := range []string{"2025-W54-1", "2025-W00", "2021-W53"} {
+		if _, ok := parseTime(input); ok {
+			t.Errorf("parseTime(%q) should fail", input)
+		}
+	}
+}
+
+func TestExtractWithOptions_Location(t *testing.T) {
+	html := `<html><head><meta property="article:modified_time" content="2025-06-15T10:30:00"></head></html>`
+	opts := Options{Location: time.FixedZone("EST", -5*3600)}
+
+	info := ExtractWithOptions("", nil, docFromHTML(html), fixedNow, opts)
+	if !info.Time.Equal(time.Date(2025, 6, 15, 15, 30, 0, 0, time.UTC)) {
+		t.Errorf("Time=%v, want 15:30 UTC", info.Time)
+	}
+}
+
+func TestFormatW3CPrecision(t *testing.T) {
+	ts := time.Date(2025, 6, 15, 22, 30, 45, 0, time.UTC)
+	berlin := time.FixedZone("CEST", 2*3600)
+
+	tests := []struct {
+		precision Precision
+		loc       *time.Location
+		want      string
+	}{
+		{PrecisionDate, nil, "2025-06-15"},
+		{"", nil, "2025-06-15"},
+		{PrecisionMinute, nil, "2025-06-15T22:30Z"},
+		{PrecisionSecond, nil, "2025-06-15T22:30:45Z"},
+		{PrecisionSecond, berlin, "2025-06-16T00:30:45+02:00"},
+		{PrecisionDate, berlin, "2025-06-16"},
+	}
+	for _, tt := range tests {
+		if got := FormatW3CPrecision(ts, tt.precision, tt.loc); got != tt.want {
+			t.Errorf("FormatW3CPrecision(%q) = %q, want %q", tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestParsePrecision(t *testing.T) {
+	if p, err := ParsePrecision("Second"); err != nil || p != PrecisionSecond {
+		t.Errorf("ParsePrecision(Second) = %q, %v", p, err)
+	}
+	if _, err := ParsePrecision("hour"); err == nil {
+		t.Error("ParsePrecision(hour) should fail")
+	}
+}
+
 func TestFormatW3C(t *testing.T) {
 	tests := []struct {
 		name string
@@ -258,6 +459,14 @@ func TestParseTime_Formats(t *testing.T) {
 		{"date only", "2025-06-15", time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
 		{"no tz", "2025-06-15T10:30:00", time.Date(2025, 6, 15, 10, 30, 0, 0, time.UTC)},
 		{"RFC1123", "Mon, 02 Jan 2006 15:04:05 MST", time.Date(2006, 1, 2, 15, 4, 5, 0, time.FixedZone("MST", 0))},
+		{"fractional seconds", "2025-06-15T10:30:00.123456Z", time.Date(2025, 6, 15, 10, 30, 0, 123456000, time.UTC)},
+		{"offset without colon", "2025-06-15T10:30:00+0000", time.Date(2025, 6, 15, 10, 30, 0, 0, time.UTC)},
+		{"fractional offset without colon", "2025-06-15T12:30:00.5+0200", time.Date(2025, 6, 15, 10, 30, 0, 500000000, time.UTC)},
+		{"minute precision", "2025-06-15T10:30+01:00", time.Date(2025, 6, 15, 9, 30, 0, 0, time.UTC)},
+		{"space separator", "2025-06-15 10:30:00", time.Date(2025, 6, 15, 10, 30, 0, 0, time.UTC)},
+		{"ISO week date", "2025-W03-2", time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"ISO week compact", "2026W011", time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{"ISO week without day", "2020-W53", time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC)},
 	}
 
 	for _, tt := range tests {
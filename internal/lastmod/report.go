@@ -0,0 +1,110 @@
+package lastmod
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultConflictThreshold is the spread between candidate dates above which
+// a page's sources are considered to disagree.
+const DefaultConflictThreshold = 24 * time.Hour
+
+// IssueType describes a lastmod problem category.
+type IssueType string
+
+const (
+	// IssueSourceConflict is reported when candidate dates on a page differ by
+	// more than the conflict threshold.
+	IssueSourceConflict IssueType = "source_conflict"
+	// IssueFutureDate is reported when a page declares a date after the crawl.
+	IssueFutureDate IssueType = "future_date"
+)
+
+// Issue represents a lastmod finding for a page.
+type Issue struct {
+	PageURL string
+	Type    IssueType
+	Detail  string
+}
+
+// Report summarizes lastmod provenance across a crawl.
+type Report struct {
+	// Total is the number of pages analyzed.
+	Total int
+	// BySource counts pages by the source of their chosen lastmod.
+	BySource map[Source]int
+	Issues   []Issue
+}
+
+// FallbackShare returns the fraction of pages whose lastmod is the crawl
+// time because no date was found (0 when no pages were analyzed).
+func (r Report) FallbackShare() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.BySource[SourceFallback]) / float64(r.Total)
+}
+
+// Analyze reports pages whose candidate dates disagree by more than
+// threshold, pages declaring dates after now, and counts pages per source.
+// A threshold <= 0 uses DefaultConflictThreshold.
+func Analyze(infos map[string]Info, now time.Time, threshold time.Duration) Report {
+	if threshold <= 0 {
+		threshold = DefaultConflictThreshold
+	}
+
+	report := Report{
+		Total:    len(infos),
+		BySource: make(map[Source]int),
+		Issues:   make([]Issue, 0),
+	}
+
+	for page, info := range infos {
+		report.BySource[info.Source]++
+
+		if len(info.Candidates) > 1 {
+			earliest, latest := info.Candidates[0], info.Candidates[0]
+			for _, c := range info.Candidates[1:] {
+				if c.Time.Before(earliest.Time) {
+					earliest = c
+				}
+				if c.Time.After(latest.Time) {
+					latest = c
+				}
+			}
+			if latest.Time.Sub(earliest.Time) > threshold {
+				report.Issues = append(report.Issues, Issue{
+					PageURL: page,
+					Type:    IssueSourceConflict,
+					Detail: fmt.Sprintf("%s %s (%s) and %s %s (%s) differ by %s",
+						earliest.Source, earliest.Field, FormatW3C(earliest.Time),
+						latest.Source, latest.Field, FormatW3C(latest.Time),
+						latest.Time.Sub(earliest.Time).Round(time.Hour)),
+				})
+			}
+		}
+
+		for _, c := range info.Candidates {
+			if c.Time.After(now) {
+				report.Issues = append(report.Issues, Issue{
+					PageURL: page,
+					Type:    IssueFutureDate,
+					Detail:  fmt.Sprintf("%s %s is in the future (%s)", c.Source, c.Field, c.Time.UTC().Format(time.RFC3339)),
+				})
+			}
+		}
+	}
+
+	sort.Slice(report.Issues, func(i, j int) bool {
+		if report.Issues[i].PageURL != report.Issues[j].PageURL {
+			return report.Issues[i].PageURL < report.Issues[j].PageURL
+		}
+		if report.Issues[i].Type != report.Issues[j].Type {
+			return report.Issues[i].Type < report.Issues[j].Type
+		}
+		return report.Issues[i].Detail < report.Issues[j].Detail
+	})
+
+	return report
+}
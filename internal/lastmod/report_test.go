@@ -0,0 +1,76 @@
+package lastmod
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyze(t *testing.T) {
+	infos := map[string]Info{
+		"https://example.com/conflict": {
+			Time:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			Source: SourceJSONLD,
+			Candidates: []Candidate{
+				{Source: SourceJSONLD, Field: "dateModified", Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+				{Source: SourceHeader, Field: "Last-Modified", Time: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		"https://example.com/close": {
+			Time:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			Source: SourceMeta,
+			Candidates: []Candidate{
+				{Source: SourceMeta, Field: "article:modified_time", Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+				{Source: SourceHeader, Field: "Last-Modified", Time: time.Date(2025, 6, 1, 6, 0, 0, 0, time.UTC)},
+			},
+		},
+		"https://example.com/future": {
+			Time:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			Source: SourceJSONLD,
+			Candidates: []Candidate{
+				{Source: SourceJSONLD, Field: "dateModified", Time: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		"https://example.com/fallback": {Time: fixedNow, Source: SourceFallback},
+	}
+
+	report := Analyze(infos, fixedNow, 0)
+
+	if report.Total != 4 || report.BySource[SourceJSONLD] != 2 || report.BySource[SourceFallback] != 1 {
+		t.Fatalf("unexpected counts: %+v", report)
+	}
+	if got := report.FallbackShare(); got != 0.25 {
+		t.Errorf("FallbackShare=%v, want 0.25", got)
+	}
+
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", report.Issues)
+	}
+	if report.Issues[0].PageURL != "https://example.com/conflict" || report.Issues[0].Type != IssueSourceConflict {
+		t.Errorf("unexpected first issue: %+v", report.Issues[0])
+	}
+	if report.Issues[1].PageURL != "https://example.com/future" || report.Issues[1].Type != IssueFutureDate {
+		t.Errorf("unexpected second issue: %+v", report.Issues[1])
+	}
+}
+
+func TestAnalyze_CustomThreshold(t *testing.T) {
+	infos := map[string]Info{
+		"https://example.com/a": {
+			Source: SourceMeta,
+			Candidates: []Candidate{
+				{Source: SourceMeta, Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+				{Source: SourceHeader, Time: time.Date(2025, 6, 1, 6, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	if report := Analyze(infos, fixedNow, time.Hour); len(report.Issues) != 1 {
+		t.Fatalf("expected conflict with 1h threshold, got %+v", report.Issues)
+	}
+}
+
+func TestReport_FallbackShareEmpty(t *testing.T) {
+	if got := (Report{}).FallbackShare(); got != 0 {
+		t.Fatalf("FallbackShare=%v, want 0", got)
+	}
+}
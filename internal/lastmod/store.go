@@ -0,0 +1,177 @@
+package lastmod
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// maxChanges bounds the change history kept per URL.
+const maxChanges = 10
+
+// Entry is the content fingerprint and last modification time recorded for
+// a URL by a previous crawl.
+type Entry struct {
+	Hash         string    `json:"hash"`
+	LastModified time.Time `json:"lastmod"`
+	// Changes are the times the content was seen to change, oldest first,
+	// capped at the most recent maxChanges.
+	Changes []time.Time `json:"changes,omitempty"`
+}
+
+// Store is a persistent, URL-keyed record of main content hashes. It lets
+// pages without a declared modification date keep the lastmod of the crawl
+// in which their content last changed, instead of claiming every crawl as a
+// change. A Store is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// LoadStore reads the store at path. A missing file yields an empty store
+// that will be created on Save.
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read lastmod store: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("parse lastmod store %s: %w", path, err)
+	}
+	if s.entries == nil {
+		s.entries = make(map[string]Entry)
+	}
+	return s, nil
+}
+
+// Resolve returns the lastmod for pageURL given the hash of its current main
+// content. When the hash matches the previous crawl the recorded lastmod is
+// kept; otherwise now is recorded and returned. The result is in UTC.
+func (s *Store) Resolve(pageURL, hash string, now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.entries[pageURL]
+	if ok && prev.Hash == hash && !prev.LastModified.IsZero() {
+		return prev.LastModified.UTC()
+	}
+
+	changes := prev.Changes
+	if len(changes) == 0 && !prev.LastModified.IsZero() {
+		// Stores written before the history was kept only know the last change.
+		changes = []time.Time{prev.LastModified.UTC()}
+	}
+	changes = append(changes, now.UTC())
+	if len(changes) > maxChanges {
+		changes = changes[len(changes)-maxChanges:]
+	}
+	s.entries[pageURL] = Entry{Hash: hash, LastModified: now.UTC(), Changes: changes}
+	return now.UTC()
+}
+
+// ChangeInterval returns the mean time between the recorded content changes
+// of pageURL. It reports false until at least two changes are known.
+func (s *Store) ChangeInterval(pageURL string) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes := s.entries[pageURL].Changes
+	if len(changes) < 2 {
+		return 0, false
+	}
+	return changes[len(changes)-1].Sub(changes[0]) / time.Duration(len(changes)-1), true
+}
+
+// Save writes the store back to its path, creating parent directories as
+// needed. The file is replaced atomically.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode lastmod store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create lastmod store directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".lastmod-*.json")
+	if err != nil {
+		return fmt.Errorf("create lastmod store file: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write lastmod store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("close lastmod store file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("replace lastmod store: %w", err)
+	}
+	return nil
+}
+
+// ContentHash returns a SHA-256 hash of a page's main content text: the
+// first <main>, <article> or role="main" element, or <body> otherwise.
+// Scripts, styles and whitespace differences are ignored so that rotating
+// tokens or reformatting do not count as content changes.
+func ContentHash(doc *goquery.Document) string {
+	if doc == nil {
+		return ""
+	}
+
+	root := doc.Find(`main, article, [role="main"]`).First()
+	if root.Length() == 0 {
+		root = doc.Find("body").First()
+	}
+	if root.Length() == 0 {
+		root = doc.Selection
+	}
+
+	words := make([]string, 0)
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			words = append(words, strings.Fields(n.Data)...)
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "noscript", "template":
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, n := range root.Nodes {
+		walk(n)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(words, " ")))
+	return hex.EncodeToString(sum[:])
+}
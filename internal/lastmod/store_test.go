@@ -0,0 +1,90 @@
+package lastmod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_KeepsLastmodWhileContentUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "lastmod.json")
+	first := time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC)
+	second := first.Add(72 * time.Hour)
+
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore: %v", err)
+	}
+	if got := store.Resolve("https://example.com/a", "h1", first); !got.Equal(first) {
+		t.Fatalf("first crawl = %v, want %v", got, first)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore: %v", err)
+	}
+	if got := reloaded.Resolve("https://example.com/a", "h1", second); !got.Equal(first) {
+		t.Errorf("unchanged content = %v, want previous lastmod %v", got, first)
+	}
+	if got := reloaded.Resolve("https://example.com/a", "h2", second); !got.Equal(second) {
+		t.Errorf("changed content = %v, want crawl time %v", got, second)
+	}
+	if got := reloaded.Resolve("https://example.com/new", "h3", second); !got.Equal(second) {
+		t.Errorf("new page = %v, want crawl time %v", got, second)
+	}
+}
+
+func TestStore_ChangeInterval(t *testing.T) {
+	store, err := LoadStore(filepath.Join(t.TempDir(), "lastmod.json"))
+	if err != nil {
+		t.Fatalf("LoadStore: %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.Resolve("https://example.com/a", "h1", start)
+	if _, ok := store.ChangeInterval("https://example.com/a"); ok {
+		t.Error("expected no interval after a single change")
+	}
+	store.Resolve("https://example.com/a", "h1", start.Add(24*time.Hour))
+	store.Resolve("https://example.com/a", "h2", start.Add(48*time.Hour))
+	store.Resolve("https://example.com/a", "h3", start.Add(96*time.Hour))
+
+	if got, ok := store.ChangeInterval("https://example.com/a"); !ok || got != 48*time.Hour {
+		t.Errorf("ChangeInterval = %v, %v, want 48h", got, ok)
+	}
+
+	for i := 0; i < 2*maxChanges; i++ {
+		store.Resolve("https://example.com/b", fmt.Sprint(i), start.Add(time.Duration(i)*time.Hour))
+	}
+	if n := len(store.entries["https://example.com/b"].Changes); n != maxChanges {
+		t.Errorf("kept %d changes, want %d", n, maxChanges)
+	}
+}
+
+func TestLoadStore_InvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lastmod.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadStore(path); err == nil {
+		t.Fatal("expected error for invalid store file")
+	}
+}
+
+func TestContentHash_IgnoresChromeAndScripts(t *testing.T) {
+	a := docFromHTML(`<html><body><nav>Menu A</nav><main><h1>Title</h1><p>Body   text</p><script>var t=1</script></main></body></html>`)
+	b := docFromHTML(`<html><body><nav>Menu B</nav><main><h1>Title</h1>
+		<p>Body text</p><script>var t=2</script></main></body></html>`)
+	c := docFromHTML(`<html><body><main><h1>Title</h1><p>New body text</p></main></body></html>`)
+
+	if ContentHash(a) != ContentHash(b) {
+		t.Error("navigation, script and whitespace changes should not change the hash")
+	}
+	if ContentHash(a) == ContentHash(c) {
+		t.Error("main content change should change the hash")
+	}
+}
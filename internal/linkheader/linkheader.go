@@ -0,0 +1,102 @@
+// Package linkheader parses HTTP Link headers (RFC 8288), such as
+//
+//	Link: <https://example.com/page>; rel="canonical"
+//	Link: <https://example.com/de/>; rel="alternate"; hreflang="de"
+package linkheader
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Link is a single link-value from a Link header.
+type Link struct {
+	// URL is the raw target between angle brackets, unresolved.
+	URL string
+	// Rel holds the lower-cased relation types from the rel parameter.
+	Rel []string
+	// Params holds every other parameter, keyed by lower-cased name.
+	Params map[string]string
+}
+
+// HasRel reports whether the link declares the given relation type.
+func (l Link) HasRel(rel string) bool {
+	for _, r := range l.Rel {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromHeader parses every Link header value in header.
+func FromHeader(header http.Header) []Link {
+	if header == nil {
+		return nil
+	}
+	links := make([]Link, 0)
+	for _, value := range header.Values("Link") {
+		links = append(links, Parse(value)...)
+	}
+	return links
+}
+
+// Parse parses a single Link header value, which may contain several
+// comma-separated links. Malformed link-values are skipped.
+func Parse(value string) []Link {
+	links := make([]Link, 0)
+	for _, part := range splitOutside(value, ',') {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(part, "<") {
+			continue
+		}
+		end := strings.Index(part, ">")
+		if end < 0 {
+			continue
+		}
+
+		link := Link{
+			URL:    strings.TrimSpace(part[1:end]),
+			Params: make(map[string]string),
+		}
+		for _, param := range splitOutside(part[end+1:], ';') {
+			name, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			val = strings.Trim(strings.TrimSpace(val), `"`)
+			if name == "rel" {
+				for _, rel := range strings.Fields(val) {
+					link.Rel = append(link.Rel, strings.ToLower(rel))
+				}
+				continue
+			}
+			link.Params[name] = val
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// splitOutside splits s on sep, ignoring separators inside angle brackets or
+// double quotes.
+func splitOutside(s string, sep rune) []string {
+	parts := make([]string, 0)
+	inQuotes, inBrackets := false, false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"' && !inBrackets:
+			inQuotes = !inQuotes
+		case r == '<' && !inQuotes:
+			inBrackets = true
+		case r == '>' && !inQuotes:
+			inBrackets = false
+		case r == sep && !inQuotes && !inBrackets:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
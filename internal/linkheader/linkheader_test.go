@@ -0,0 +1,48 @@
+package linkheader
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParse_MultipleLinks(t *testing.T) {
+	links := Parse(`<https://example.com/de/>; rel="alternate"; hreflang="de", <https://example.com/a,b>; rel=canonical`)
+	if len(links) != 2 {
+		t.Fatalf("got %d links, want 2: %+v", len(links), links)
+	}
+
+	if links[0].URL != "https://example.com/de/" || !links[0].HasRel("alternate") || links[0].Params["hreflang"] != "de" {
+		t.Errorf("unexpected first link: %+v", links[0])
+	}
+	if links[1].URL != "https://example.com/a,b" || !links[1].HasRel("CANONICAL") {
+		t.Errorf("unexpected second link: %+v", links[1])
+	}
+}
+
+func TestParse_MultipleRelTypes(t *testing.T) {
+	links := Parse(`</page>; rel="canonical alternate"`)
+	if len(links) != 1 || !links[0].HasRel("canonical") || !links[0].HasRel("alternate") {
+		t.Fatalf("unexpected links: %+v", links)
+	}
+}
+
+func TestParse_SkipsMalformed(t *testing.T) {
+	links := Parse(`https://example.com/; rel=canonical, <https://example.com/ok>; rel=canonical`)
+	if len(links) != 1 || links[0].URL != "https://example.com/ok" {
+		t.Fatalf("unexpected links: %+v", links)
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `<https://example.com/a>; rel=canonical`)
+	header.Add("Link", `<https://example.com/b>; rel=preload`)
+
+	links := FromHeader(header)
+	if len(links) != 2 {
+		t.Fatalf("got %d links, want 2", len(links))
+	}
+	if FromHeader(nil) != nil {
+		t.Error("FromHeader(nil) should return nil")
+	}
+}
@@ -0,0 +1,425 @@
+// Package media extracts video and news metadata from crawled pages for the
+// Google video and news sitemap extensions, and validates that the fields
+// those extensions require are present.
+//
+// Videos are read from <video> elements and schema.org VideoObject JSON-LD;
+// news articles are read from NewsArticle (and subtype) JSON-LD.
+package media
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tariktz/gopherseo/internal/lastmod"
+)
+
+// NewsWindow is how far back an article's publication date may lie for it to
+// be listed in a news sitemap.
+const NewsWindow = 48 * time.Hour
+
+// Kind identifies the sitemap extension an Issue relates to.
+type Kind string
+
+const (
+	KindVideo Kind = "video"
+	KindNews  Kind = "news"
+)
+
+// Video describes one video embedded on a page.
+type Video struct {
+	PageURL      string
+	ThumbnailURL string
+	Title        string
+	Description  string
+	ContentURL   string
+	PlayerURL    string
+	// Duration is the video length; zero when unknown.
+	Duration time.Duration
+	// PublicationDate is the upload date; zero when unknown.
+	PublicationDate time.Time
+}
+
+// NewsArticle describes a NewsArticle declared on a page.
+type NewsArticle struct {
+	PageURL         string
+	Title           string
+	PublicationName string
+	Language        string
+	// PublicationDate is zero when missing or unparsable.
+	PublicationDate time.Time
+}
+
+// Issue reports required sitemap fields missing for a video or article.
+type Issue struct {
+	PageURL string
+	Kind    Kind
+	// Subject identifies the video (content/player URL or title) or article
+	// (headline) within the page.
+	Subject string
+	Missing []string
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ExtractVideos returns the videos declared on a page. VideoObject JSON-LD
+// entries come first; <video> elements whose source matches a JSON-LD entry
+// only fill fields the structured data left empty.
+func ExtractVideos(pageURL string, doc *goquery.Document) []Video {
+	if doc == nil {
+		return nil
+	}
+
+	videos := make([]Video, 0)
+	for _, obj := range jsonLDObjects(doc) {
+		if !hasType(obj, "VideoObject") {
+			continue
+		}
+		v := Video{
+			PageURL:      pageURL,
+			ThumbnailURL: resolve(pageURL, firstString(obj["thumbnailUrl"])),
+			Title:        firstString(obj["name"]),
+			Description:  firstString(obj["description"]),
+			ContentURL:   resolve(pageURL, firstString(obj["contentUrl"])),
+			PlayerURL:    resolve(pageURL, firstString(obj["embedUrl"])),
+			Duration:     parseISODuration(firstString(obj["duration"])),
+		}
+		if t, ok := lastmod.ParseTime(firstString(obj["uploadDate"])); ok {
+			v.PublicationDate = t.UTC()
+		}
+		videos = append(videos, v)
+	}
+
+	doc.Find("video").Each(func(_ int, s *goquery.Selection) {
+		src := strings.TrimSpace(s.AttrOr("src", ""))
+		if src == "" {
+			src = strings.TrimSpace(s.Find("source[src]").First().AttrOr("src", ""))
+		}
+		v := Video{
+			PageURL:      pageURL,
+			ThumbnailURL: resolve(pageURL, strings.TrimSpace(s.AttrOr("poster", ""))),
+			Title:        strings.TrimSpace(s.AttrOr("title", s.AttrOr("aria-label", ""))),
+			ContentURL:   resolve(pageURL, src),
+		}
+		if v.ContentURL == "" {
+			return
+		}
+
+		for i := range videos {
+			if videos[i].ContentURL == v.ContentURL {
+				videos[i] = mergeVideo(videos[i], v)
+				return
+			}
+		}
+		videos = append(videos, v)
+	})
+
+	return videos
+}
+
+// newsTypes lists NewsArticle and its schema.org subtypes.
+var newsTypes = []string{
+	"NewsArticle",
+	"AnalysisNewsArticle",
+	"AskPublicNewsArticle",
+	"BackgroundNewsArticle",
+	"OpinionNewsArticle",
+	"ReportageNewsArticle",
+	"ReviewNewsArticle",
+}
+
+// ExtractNews returns the first NewsArticle, or NewsArticle subtype such as
+// ReportageNewsArticle, declared in JSON-LD on the page. When the article has
+// no inLanguage, the <html lang> attribute is used.
+func ExtractNews(pageURL string, doc *goquery.Document) (NewsArticle, bool) {
+	if doc == nil {
+		return NewsArticle{}, false
+	}
+
+	for _, obj := range jsonLDObjects(doc) {
+		if !hasType(obj, newsTypes...) {
+			continue
+		}
+
+		article := NewsArticle{
+			PageURL:         pageURL,
+			Title:           firstString(obj["headline"]),
+			PublicationName: publisherName(obj["publisher"]),
+			Language:        firstString(obj["inLanguage"]),
+		}
+		if article.Language == "" {
+			article.Language = strings.TrimSpace(doc.Find("html").AttrOr("lang", ""))
+		}
+		article.Language = newsLanguage(article.Language)
+		if t, ok := lastmod.ParseTime(firstString(obj["datePublished"])); ok {
+			article.PublicationDate = t.UTC()
+		}
+		return article, true
+	}
+
+	return NewsArticle{}, false
+}
+
+// MissingVideoFields lists the fields the video sitemap extension requires
+// that v does not provide.
+func MissingVideoFields(v Video) []string {
+	missing := make([]string, 0)
+	if v.ThumbnailURL == "" {
+		missing = append(missing, "thumbnail_loc")
+	}
+	if v.Title == "" {
+		missing = append(missing, "title")
+	}
+	if v.Description == "" {
+		missing = append(missing, "description")
+	}
+	if v.ContentURL == "" && v.PlayerURL == "" {
+		missing = append(missing, "content_loc or player_loc")
+	}
+	return missing
+}
+
+// MissingNewsFields lists the fields the news sitemap extension requires that
+// a does not provide.
+func MissingNewsFields(a NewsArticle) []string {
+	missing := make([]string, 0)
+	if a.PublicationName == "" {
+		missing = append(missing, "publication name")
+	}
+	if a.Language == "" {
+		missing = append(missing, "publication language")
+	}
+	if a.PublicationDate.IsZero() {
+		missing = append(missing, "publication_date")
+	}
+	if a.Title == "" {
+		missing = append(missing, "title")
+	}
+	return missing
+}
+
+// IsRecent reports whether the article was published within NewsWindow
+// before now.
+func IsRecent(a NewsArticle, now time.Time) bool {
+	if a.PublicationDate.IsZero() {
+		return false
+	}
+	age := now.Sub(a.PublicationDate)
+	return age >= 0 && age <= NewsWindow
+}
+
+// Validate returns an Issue for every video and article that lacks fields
+// required by its sitemap extension, sorted by page URL.
+func Validate(videosByPage map[string][]Video, newsByPage map[string]NewsArticle) []Issue {
+	issues := make([]Issue, 0)
+
+	for page, videos := range videosByPage {
+		for _, v := range videos {
+			if missing := MissingVideoFields(v); len(missing) > 0 {
+				issues = append(issues, Issue{PageURL: page, Kind: KindVideo, Subject: videoSubject(v), Missing: missing})
+			}
+		}
+	}
+
+	for page, article := range newsByPage {
+		if missing := MissingNewsFields(article); len(missing) > 0 {
+			issues = append(issues, Issue{PageURL: page, Kind: KindNews, Subject: article.Title, Missing: missing})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].PageURL != issues[j].PageURL {
+			return issues[i].PageURL < issues[j].PageURL
+		}
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Subject < issues[j].Subject
+	})
+
+	return issues
+}
+
+func videoSubject(v Video) string {
+	switch {
+	case v.ContentURL != "":
+		return v.ContentURL
+	case v.PlayerURL != "":
+		return v.PlayerURL
+	default:
+		return v.Title
+	}
+}
+
+func mergeVideo(primary, fallback Video) Video {
+	if primary.ThumbnailURL == "" {
+		primary.ThumbnailURL = fallback.ThumbnailURL
+	}
+	if primary.Title == "" {
+		primary.Title = fallback.Title
+	}
+	if primary.Description == "" {
+		primary.Description = fallback.Description
+	}
+	return primary
+}
+
+// jsonLDObjects returns every JSON object found in the page's JSON-LD
+// blocks, flattening top-level arrays and @graph containers.
+func jsonLDObjects(doc *goquery.Document) []map[string]interface{} {
+	objects := make([]map[string]interface{}, 0)
+
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch typed := v.(type) {
+		case []interface{}:
+			for _, item := range typed {
+				collect(item)
+			}
+		case map[string]interface{}:
+			objects = append(objects, typed)
+			if graph, ok := typed["@graph"]; ok {
+				collect(graph)
+			}
+		}
+	}
+
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		raw := strings.TrimSpace(s.Text())
+		if raw == "" {
+			return
+		}
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			return
+		}
+		collect(parsed)
+	})
+
+	return objects
+}
+
+// hasType reports whether a JSON-LD object's @type (string or array)
+// contains one of want.
+func hasType(obj map[string]interface{}, want ...string) bool {
+	var types []interface{}
+	switch typed := obj["@type"].(type) {
+	case string:
+		types = []interface{}{typed}
+	case []interface{}:
+		types = typed
+	}
+	for _, item := range types {
+		if s, ok := item.(string); ok && slices.Contains(want, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstString returns a trimmed string value from a JSON-LD property that
+// may be a string, an array, or an object with a "url"/"@id" key.
+func firstString(v interface{}) string {
+	switch typed := v.(type) {
+	case string:
+		return strings.TrimSpace(typed)
+	case []interface{}:
+		for _, item := range typed {
+			if s := firstString(item); s != "" {
+				return s
+			}
+		}
+	case map[string]interface{}:
+		if s := firstString(typed["url"]); s != "" {
+			return s
+		}
+		return firstString(typed["@id"])
+	}
+	return ""
+}
+
+func publisherName(v interface{}) string {
+	switch typed := v.(type) {
+	case string:
+		return strings.TrimSpace(typed)
+	case map[string]interface{}:
+		return firstString(typed["name"])
+	case []interface{}:
+		for _, item := range typed {
+			if name := publisherName(item); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// newsLanguage converts a BCP 47 tag into the ISO 639 code expected by news
+// sitemaps. Chinese keeps its script/region variant (zh-cn, zh-tw).
+func newsLanguage(tag string) string {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if tag == "" {
+		return ""
+	}
+	primary, rest, _ := strings.Cut(tag, "-")
+	if primary == "zh" && rest != "" {
+		switch {
+		case strings.Contains(rest, "hant"), strings.Contains(rest, "tw"), strings.Contains(rest, "hk"):
+			return "zh-tw"
+		default:
+			return "zh-cn"
+		}
+	}
+	return primary
+}
+
+func resolve(pageURL, href string) string {
+	if href == "" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return href
+	}
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(parsed).String()
+}
+
+// parseISODuration parses an ISO 8601 duration such as PT1M30S. It returns
+// zero for empty or unsupported values.
+func parseISODuration(raw string) time.Duration {
+	match := isoDurationPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(raw)))
+	if match == nil {
+		return 0
+	}
+
+	var total time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return 0
+		}
+		total += time.Duration(n) * unit
+	}
+	if match[4] != "" {
+		seconds, err := strconv.ParseFloat(match[4], 64)
+		if err != nil {
+			return 0
+		}
+		total += time.Duration(seconds * float64(time.Second))
+	}
+	return total
+}
@@ -0,0 +1,175 @@
+package media
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func docFromHTML(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("build document: %v", err)
+	}
+	return doc
+}
+
+func TestExtractVideos_JSONLDMergedWithVideoElement(t *testing.T) {
+	doc := docFromHTML(t, `<html><head>
+	<script type="application/ld+json">
+	{"@graph":[{"@type":"VideoObject","name":"Launch","description":"Product launch",
+	  "contentUrl":"/media/launch.mp4","duration":"PT1M30S","uploadDate":"2025-06-01"}]}
+	</script></head><body>
+	<video src="/media/launch.mp4" poster="/media/launch.jpg"></video>
+	<video poster="/media/teaser.jpg" title="Teaser"><source src="https://cdn.example.net/teaser.mp4"></video>
+	</body></html>`)
+
+	videos := ExtractVideos("https://example.com/launch", doc)
+	if len(videos) != 2 {
+		t.Fatalf("got %d videos, want 2: %+v", len(videos), videos)
+	}
+
+	launch := videos[0]
+	if launch.ContentURL != "https://example.com/media/launch.mp4" {
+		t.Errorf("ContentURL=%q", launch.ContentURL)
+	}
+	if launch.ThumbnailURL != "https://example.com/media/launch.jpg" {
+		t.Errorf("ThumbnailURL=%q, want poster merged from <video>", launch.ThumbnailURL)
+	}
+	if launch.Duration != 90*time.Second {
+		t.Errorf("Duration=%v, want 90s", launch.Duration)
+	}
+	if !launch.PublicationDate.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PublicationDate=%v", launch.PublicationDate)
+	}
+	if missing := MissingVideoFields(launch); len(missing) != 0 {
+		t.Errorf("launch should be complete, missing %v", missing)
+	}
+
+	teaser := videos[1]
+	if teaser.ContentURL != "https://cdn.example.net/teaser.mp4" || teaser.Title != "Teaser" {
+		t.Errorf("unexpected teaser: %+v", teaser)
+	}
+	if missing := MissingVideoFields(teaser); strings.Join(missing, ",") != "description" {
+		t.Errorf("teaser missing=%v, want [description]", missing)
+	}
+}
+
+func TestExtractNews(t *testing.T) {
+	doc := docFromHTML(t, `<html lang="de-AT"><head>
+	<script type="application/ld+json">
+	[{"@type":"BreadcrumbList"},{"@type":["NewsArticle"],"headline":"Election results",
+	  "datePublished":"2026-10-17T08:00:00Z","publisher":{"@type":"Organization","name":"Daily"}}]
+	</script></head><body></body></html>`)
+
+	article, ok := ExtractNews("https://example.com/news/election", doc)
+	if !ok {
+		t.Fatal("expected NewsArticle to be found")
+	}
+	if article.Title != "Election results" || article.PublicationName != "Daily" || article.Language != "de" {
+		t.Errorf("unexpected article: %+v", article)
+	}
+	if missing := MissingNewsFields(article); len(missing) != 0 {
+		t.Errorf("article should be complete, missing %v", missing)
+	}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	if !IsRecent(article, now) {
+		t.Error("article from yesterday should be recent")
+	}
+	if IsRecent(article, now.Add(72*time.Hour)) {
+		t.Error("article older than 48 hours should not be recent")
+	}
+}
+
+func TestExtractNews_Subtypes(t *testing.T) {
+	types := []string{
+		`"ReportageNewsArticle"`,
+		`"AnalysisNewsArticle"`,
+		`"OpinionNewsArticle"`,
+		`"BackgroundNewsArticle"`,
+		`"ReviewNewsArticle"`,
+		`["Article","NewsArticle"]`,
+		`["CreativeWork","OpinionNewsArticle"]`,
+	}
+	for _, typ := range types {
+		doc := docFromHTML(t, `<html lang="en"><head><script type="application/ld+json">
+		{"@type":`+typ+`,"headline":"Headline","datePublished":"2026-10-17T08:00:00Z","publisher":{"name":"Daily"}}
+		</script></head></html>`)
+
+		article, ok := ExtractNews("https://example.com/news", doc)
+		if !ok {
+			t.Errorf("@type %s: expected a news article", typ)
+			continue
+		}
+		if article.Title != "Headline" || article.PublicationName != "Daily" {
+			t.Errorf("@type %s: unexpected article %+v", typ, article)
+		}
+	}
+}
+
+func TestExtractNews_NotPresent(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><script type="application/ld+json">{"@type":"Article"}</script></head></html>`)
+
+	if _, ok := ExtractNews("https://example.com/", doc); ok {
+		t.Fatal("Article should not be treated as NewsArticle")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	videos := map[string][]Video{
+		"https://example.com/v": {{ContentURL: "https://example.com/v.mp4", Title: "V"}},
+	}
+	news := map[string]NewsArticle{
+		"https://example.com/n": {Title: "Headline", Language: "en"},
+	}
+
+	issues := Validate(videos, news)
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2: %+v", len(issues), issues)
+	}
+	if issues[0].Kind != KindNews || strings.Join(issues[0].Missing, ",") != "publication name,publication_date" {
+		t.Errorf("unexpected news issue: %+v", issues[0])
+	}
+	if issues[1].Kind != KindVideo || issues[1].Subject != "https://example.com/v.mp4" {
+		t.Errorf("unexpected video issue: %+v", issues[1])
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"PT1M30S", 90 * time.Second},
+		{"PT2H", 2 * time.Hour},
+		{"P1DT1S", 24*time.Hour + time.Second},
+		{"PT0.5S", 500 * time.Millisecond},
+		{"90", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseISODuration(tt.input); got != tt.want {
+			t.Errorf("parseISODuration(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNewsLanguage(t *testing.T) {
+	tests := map[string]string{
+		"en-US":   "en",
+		"zh-Hant": "zh-tw",
+		"zh-CN":   "zh-cn",
+		"fr":      "fr",
+		"":        "",
+	}
+	for input, want := range tests {
+		if got := newsLanguage(input); got != want {
+			t.Errorf("newsLanguage(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
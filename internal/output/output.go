@@ -8,24 +8,41 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tariktz/gopherseo/internal/canonical"
 	"github.com/tariktz/gopherseo/internal/crawler"
+	"github.com/tariktz/gopherseo/internal/hreflang"
+	"github.com/tariktz/gopherseo/internal/images"
+	"github.com/tariktz/gopherseo/internal/lastmod"
+	"github.com/tariktz/gopherseo/internal/media"
+	"github.com/tariktz/gopherseo/internal/robots"
+	"github.com/tariktz/gopherseo/internal/sitemap"
 )
 
-// sitemapURLSet is the root element of a Sitemap 0.9 XML document.
-type sitemapURLSet struct {
-	XMLName xml.Name     `xml:"urlset"`
-	Xmlns   string       `xml:"xmlns,attr"`
-	URLs    []sitemapURL `xml:"url"`
-}
-
-// sitemapURL represents a single <url> entry.
-type sitemapURL struct {
-	Loc     string `xml:"loc"`
-	LastMod string `xml:"lastmod,omitempty"`
+// SitemapOptions configures optional sitemap content.
+type SitemapOptions struct {
+	// LastModified maps URLs to their <lastmod> value.
+	LastModified map[string]time.Time
+	// LastmodPrecision sets the <lastmod> granularity. Empty writes a W3C
+	// date (YYYY-MM-DD).
+	LastmodPrecision lastmod.Precision
+	// Location is the time zone <lastmod> values are written in. Nil means
+	// UTC.
+	Location *time.Location
+	// Changefreq maps URLs to their <changefreq> value.
+	Changefreq map[string]string
+	// Priority maps URLs to their <priority> value (0.0-1.0).
+	Priority map[string]float64
+	// Images maps URLs to the image URLs listed as <image:image> entries.
+	// At most images.MaxSitemapImages entries are written per URL.
+	Images map[string][]string
+	// Alternates maps URLs to the hreflang alternates written as
+	// <xhtml:link rel="alternate"> entries.
+	Alternates map[string][]hreflang.Alternate
 }
 
 // WriteSitemap creates a Sitemap 0.9 XML file at outputPath containing the
@@ -33,27 +50,138 @@ type sitemapURL struct {
 // populated with the corresponding W3C date (YYYY-MM-DD). Parent directories
 // are created automatically.
 func WriteSitemap(outputPath string, urls []string, lastModifiedMap map[string]time.Time) error {
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("create output directory: %w", err)
+	return WriteSitemapWithOptions(outputPath, urls, SitemapOptions{LastModified: lastModifiedMap})
+}
+
+// WriteSitemapWithOptions creates a Sitemap 0.9 XML file at outputPath like
+// WriteSitemap, adding the optional content configured in opts. Extension
+// namespaces are only declared when the document uses them.
+func WriteSitemapWithOptions(outputPath string, urls []string, opts SitemapOptions) error {
+	urlset := sitemap.URLSet{
+		Xmlns: sitemap.Namespace,
+		URLs:  make([]sitemap.URL, 0, len(urls)),
+	}
+	for _, link := range urls {
+		u := sitemap.URL{Loc: link}
+		if opts.LastModified != nil {
+			if t, ok := opts.LastModified[link]; ok {
+				u.LastMod = lastmod.FormatW3CPrecision(t, opts.LastmodPrecision, opts.Location)
+			}
+		}
+		u.ChangeFreq = opts.Changefreq[link]
+		if p, ok := opts.Priority[link]; ok {
+			u.Priority = formatPriority(p)
+		}
+		for _, alt := range opts.Alternates[link] {
+			u.Links = append(u.Links, sitemap.Link{Rel: "alternate", Hreflang: alt.Hreflang, Href: alt.URL})
+		}
+		if len(u.Links) > 0 {
+			urlset.XmlnsXHTML = sitemap.XHTMLNamespace
+		}
+		for _, imageURL := range opts.Images[link] {
+			if len(u.Images) >= images.MaxSitemapImages {
+				break
+			}
+			u.Images = append(u.Images, sitemap.Image{Loc: imageURL})
+		}
+		if len(u.Images) > 0 {
+			urlset.XmlnsImage = sitemap.ImageNamespace
+		}
+		urlset.URLs = append(urlset.URLs, u)
 	}
 
-	f, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("create output file: %w", err)
+	return writeURLSet(outputPath, urlset)
+}
+
+// formatPriority writes a priority with as many decimals as it needs, but at
+// least one, so 1 is "1.0" and 0.85 stays "0.85".
+func formatPriority(p float64) string {
+	s := strconv.FormatFloat(p, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
 	}
+	return s
+}
 
-	urlset := sitemapURLSet{
-		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
-		URLs:  make([]sitemapURL, 0, len(urls)),
+// WriteVideoSitemap creates a sitemap at outputPath using the Google video
+// extension. Each page in videosByPage becomes a <url> with one
+// <video:video> entry per video; videos missing required fields (see
+// media.MissingVideoFields) are skipped, as are pages left without videos.
+func WriteVideoSitemap(outputPath string, videosByPage map[string][]media.Video) error {
+	urlset := sitemap.URLSet{
+		Xmlns:      sitemap.Namespace,
+		XmlnsVideo: sitemap.VideoNamespace,
+		URLs:       make([]sitemap.URL, 0, len(videosByPage)),
 	}
-	for _, link := range urls {
-		u := sitemapURL{Loc: link}
-		if lastModifiedMap != nil {
-			if t, ok := lastModifiedMap[link]; ok {
-				u.LastMod = t.UTC().Format("2006-01-02")
+
+	for _, page := range sortedPages(videosByPage) {
+		u := sitemap.URL{Loc: page}
+		for _, v := range videosByPage[page] {
+			if len(media.MissingVideoFields(v)) > 0 {
+				continue
+			}
+			entry := sitemap.Video{
+				ThumbnailLoc: v.ThumbnailURL,
+				Title:        v.Title,
+				Description:  v.Description,
+				ContentLoc:   v.ContentURL,
+				PlayerLoc:    v.PlayerURL,
+				Duration:     int(v.Duration.Seconds()),
 			}
+			if !v.PublicationDate.IsZero() {
+				entry.PublicationDate = v.PublicationDate.UTC().Format(time.RFC3339)
+			}
+			u.Videos = append(u.Videos, entry)
 		}
-		urlset.URLs = append(urlset.URLs, u)
+		if len(u.Videos) > 0 {
+			urlset.URLs = append(urlset.URLs, u)
+		}
+	}
+
+	return writeURLSet(outputPath, urlset)
+}
+
+// WriteNewsSitemap creates a sitemap at outputPath using the Google news
+// extension. Only articles with every required field that were published
+// within media.NewsWindow before now are included.
+func WriteNewsSitemap(outputPath string, newsByPage map[string]media.NewsArticle, now time.Time) error {
+	urlset := sitemap.URLSet{
+		Xmlns:     sitemap.Namespace,
+		XmlnsNews: sitemap.NewsNamespace,
+		URLs:      make([]sitemap.URL, 0, len(newsByPage)),
+	}
+
+	for _, page := range sortedPages(newsByPage) {
+		article := newsByPage[page]
+		if len(media.MissingNewsFields(article)) > 0 || !media.IsRecent(article, now) {
+			continue
+		}
+		urlset.URLs = append(urlset.URLs, sitemap.URL{
+			Loc: page,
+			News: &sitemap.News{
+				Publication: sitemap.NewsPublication{
+					Name:     article.PublicationName,
+					Language: article.Language,
+				},
+				PublicationDate: article.PublicationDate.UTC().Format(time.RFC3339),
+				Title:           article.Title,
+			},
+		})
+	}
+
+	return writeURLSet(outputPath, urlset)
+}
+
+// writeURLSet encodes urlset as an indented XML document at outputPath,
+// creating parent directories as needed.
+func writeURLSet(outputPath string, urlset sitemap.URLSet) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
 	}
 
 	if _, err := f.Write([]byte(xml.Header)); err != nil {
@@ -75,6 +203,15 @@ func WriteSitemap(outputPath string, urls []string, lastModifiedMap map[string]t
 	return nil
 }
 
+func sortedPages[V any](byPage map[string]V) []string {
+	pages := make([]string, 0, len(byPage))
+	for page := range byPage {
+		pages = append(pages, page)
+	}
+	sort.Strings(pages)
+	return pages
+}
+
 // WriteIssueTasks creates a Markdown checklist at outputPath documenting every
 // broken link and the source pages that reference it.
 func WriteIssueTasks(outputPath string, tasks []crawler.BrokenLinkTask) error {
@@ -116,36 +253,119 @@ func WriteIssueTasks(outputPath string, tasks []crawler.BrokenLinkTask) error {
 		return flushAndClose()
 	}
 
+	for g, group := range groupBrokenLinkTasks(tasks) {
+		heading := fmt.Sprintf("## %s (%d)\n\n", group.title, len(group.tasks))
+		if g > 0 {
+			heading = "\n" + heading
+		}
+		if _, err := w.WriteString(heading); err != nil {
+			return writeErr("write task group heading", err)
+		}
+
+		if err := writeBrokenLinkTasks(w, group.tasks); err != nil {
+			return writeErr("write task item", err)
+		}
+	}
+
+	return flushAndClose()
+}
+
+// brokenLinkGroup is a section of the broken-link report.
+type brokenLinkGroup struct {
+	title string
+	tasks []crawler.BrokenLinkTask
+}
+
+// errorClassTitles names the broken-link report section of each error class.
+var errorClassTitles = map[crawler.ErrorClass]string{
+	crawler.ErrorDNS:              "DNS failures",
+	crawler.ErrorRefused:          "Connections refused",
+	crawler.ErrorTLS:              "TLS errors",
+	crawler.ErrorTimeout:          "Timeouts",
+	crawler.ErrorTooManyRedirects: "Too many redirects",
+	crawler.ErrorBodyTooLarge:     "Bodies too large",
+	crawler.ErrorConnection:       "Connections dropped",
+	crawler.ErrorOther:            "Other request failures",
+}
+
+// groupBrokenLinkTasks splits tasks into HTTP errors followed by one group
+// per error class in crawler.ErrorClasses order, keeping the task order
+// within each group and skipping empty groups.
+func groupBrokenLinkTasks(tasks []crawler.BrokenLinkTask) []brokenLinkGroup {
+	byClass := make(map[crawler.ErrorClass][]crawler.BrokenLinkTask)
+	for _, task := range tasks {
+		class := task.ErrorClass
+		if class == "" && task.Status == 0 {
+			class = crawler.ErrorOther
+		}
+		byClass[class] = append(byClass[class], task)
+	}
+
+	groups := make([]brokenLinkGroup, 0, len(byClass))
+	if httpTasks := byClass[""]; len(httpTasks) > 0 {
+		groups = append(groups, brokenLinkGroup{title: "HTTP errors", tasks: httpTasks})
+	}
+	for _, class := range crawler.ErrorClasses {
+		if classTasks := byClass[class]; len(classTasks) > 0 {
+			groups = append(groups, brokenLinkGroup{title: errorClassTitles[class], tasks: classTasks})
+		}
+	}
+	return groups
+}
+
+// writeBrokenLinkTasks writes one checklist item per task, separated by
+// blank lines.
+func writeBrokenLinkTasks(w *bufio.Writer, tasks []crawler.BrokenLinkTask) error {
 	for i, task := range tasks {
 		statusLabel := strconv.Itoa(task.Status)
 		if task.Status == 0 {
 			statusLabel = "request_failed"
 		}
+		if task.Soft404Reason != "" {
+			statusLabel += ", soft_404"
+		}
 
 		if _, err := fmt.Fprintf(w, "- [ ] Fix `%s` (status: %s)\n", task.URL, statusLabel); err != nil {
-			return writeErr("write task item", err)
+			return err
+		}
+
+		if task.Soft404Reason != "" {
+			if _, err := fmt.Fprintf(w, "  - Soft 404: %s\n", task.Soft404Reason); err != nil {
+				return err
+			}
+		}
+
+		if task.Error != "" {
+			if _, err := fmt.Fprintf(w, "  - Error: %s\n", task.Error); err != nil {
+				return err
+			}
+		}
+		if task.Attempts > 1 {
+			if _, err := fmt.Fprintf(w, "  - Attempts: %d\n", task.Attempts); err != nil {
+				return err
+			}
 		}
 
 		if len(task.Sources) == 0 {
 			if _, err := w.WriteString("  - Found on: (source page not captured)\n"); err != nil {
-				return writeErr("write task source fallback", err)
+				return err
 			}
 		} else {
 			for _, source := range task.Sources {
 				if _, err := fmt.Fprintf(w, "  - Found on: `%s`\n", source); err != nil {
-					return writeErr("write task source", err)
+					return err
 				}
 			}
 		}
 
 		if i < len(tasks)-1 {
 			if _, err := w.WriteString("\n"); err != nil {
-				return writeErr("write task separator", err)
+				return err
 			}
 		}
 	}
 
-	return flushAndClose()
+	return nil
 }
 
 // WriteCanonicalIssues creates a Markdown checklist at outputPath documenting
@@ -216,3 +436,849 @@ func WriteCanonicalIssues(outputPath string, issues []canonical.Issue) error {
 
 	return flushAndClose()
 }
+
+// WriteImageReport creates a Markdown checklist at outputPath documenting
+// every image with at least one SEO finding (missing/empty alt, missing
+// dimensions, oversize or failed fetch) and the pages embedding it.
+func WriteImageReport(outputPath string, audits []images.Audit) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create image report output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create image report output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush image report file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close image report file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# Image SEO Tasks\n\n"); err != nil {
+		return writeErr("write image report header", err)
+	}
+
+	withIssues := make([]images.Audit, 0, len(audits))
+	for _, audit := range audits {
+		if audit.HasIssues() {
+			withIssues = append(withIssues, audit)
+		}
+	}
+
+	if len(withIssues) == 0 {
+		if _, err := fmt.Fprintf(w, "No image issues were found across %d images in this crawl.\n", len(audits)); err != nil {
+			return writeErr("write no-image-issues message", err)
+		}
+		return flushAndClose()
+	}
+
+	writePages := func(label string, pages []string) error {
+		for _, page := range pages {
+			if _, err := fmt.Fprintf(w, "  - %s: `%s`\n", label, page); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i, audit := range withIssues {
+		statusLabel := strconv.Itoa(audit.Status)
+		if audit.Status == 0 {
+			statusLabel = "request_failed"
+		}
+		sizeLabel := "unknown"
+		if audit.Size >= 0 {
+			sizeLabel = strconv.FormatInt(audit.Size, 10) + " bytes"
+		}
+		contentType := audit.ContentType
+		if contentType == "" {
+			contentType = "unknown"
+		}
+
+		if _, err := fmt.Fprintf(w, "- [ ] Review image `%s` (status: %s, type: %s, size: %s)\n", audit.URL, statusLabel, contentType, sizeLabel); err != nil {
+			return writeErr("write image task item", err)
+		}
+		if audit.Error != "" {
+			if _, err := fmt.Fprintf(w, "  - Fetch error: %s\n", audit.Error); err != nil {
+				return writeErr("write image fetch error", err)
+			}
+		}
+		if audit.Oversize {
+			if _, err := w.WriteString("  - Oversize: exceeds the configured byte budget\n"); err != nil {
+				return writeErr("write image oversize note", err)
+			}
+		}
+		if err := writePages("Missing alt on", audit.MissingAlt); err != nil {
+			return writeErr("write image missing alt pages", err)
+		}
+		if err := writePages("Empty alt (decorative) on", audit.EmptyAlt); err != nil {
+			return writeErr("write image empty alt pages", err)
+		}
+		if err := writePages("Missing width/height on", audit.MissingDimensions); err != nil {
+			return writeErr("write image missing dimension pages", err)
+		}
+		if err := writePages("Embedded on", audit.Pages); err != nil {
+			return writeErr("write image pages", err)
+		}
+
+		if i < len(withIssues)-1 {
+			if _, err := w.WriteString("\n"); err != nil {
+				return writeErr("write image task separator", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+// WriteMediaIssues creates a Markdown checklist at outputPath documenting
+// videos and news articles that are missing fields required by the video and
+// news sitemap extensions.
+func WriteMediaIssues(outputPath string, issues []media.Issue) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create media output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create media output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush media issues file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close media issues file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# Video and News Sitemap Tasks\n\n"); err != nil {
+		return writeErr("write media header", err)
+	}
+
+	if len(issues) == 0 {
+		if _, err := w.WriteString("No video or news sitemap issues were found in this crawl.\n"); err != nil {
+			return writeErr("write no-media-issues message", err)
+		}
+		return flushAndClose()
+	}
+
+	for i, issue := range issues {
+		if _, err := fmt.Fprintf(w, "- [ ] Complete %s metadata on `%s`\n", issue.Kind, issue.PageURL); err != nil {
+			return writeErr("write media task item", err)
+		}
+		if issue.Subject != "" {
+			if _, err := fmt.Fprintf(w, "  - Subject: %s\n", issue.Subject); err != nil {
+				return writeErr("write media task subject", err)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "  - Missing: %s\n", strings.Join(issue.Missing, ", ")); err != nil {
+			return writeErr("write media task missing fields", err)
+		}
+
+		if i < len(issues)-1 {
+			if _, err := w.WriteString("\n"); err != nil {
+				return writeErr("write media task separator", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+// WriteHreflangIssues creates a Markdown checklist at outputPath documenting
+// hreflang validation issues found during crawl.
+func WriteHreflangIssues(outputPath string, issues []hreflang.Issue) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create hreflang output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create hreflang output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush hreflang issues file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close hreflang issues file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# Hreflang Cleanup Tasks\n\n"); err != nil {
+		return writeErr("write hreflang header", err)
+	}
+
+	if len(issues) == 0 {
+		if _, err := w.WriteString("No hreflang issues were found in this crawl.\n"); err != nil {
+			return writeErr("write no-hreflang-issues message", err)
+		}
+		return flushAndClose()
+	}
+
+	for i, issue := range issues {
+		if _, err := fmt.Fprintf(w, "- [ ] Resolve hreflang issue on `%s`\n", issue.PageURL); err != nil {
+			return writeErr("write hreflang task item", err)
+		}
+		if _, err := fmt.Fprintf(w, "  - Type: `%s`\n", issue.Type); err != nil {
+			return writeErr("write hreflang task type", err)
+		}
+		if issue.Hreflang != "" {
+			if _, err := fmt.Fprintf(w, "  - Hreflang: `%s`\n", issue.Hreflang); err != nil {
+				return writeErr("write hreflang task code", err)
+			}
+		}
+		if issue.TargetURL != "" {
+			if _, err := fmt.Fprintf(w, "  - Alternate: `%s`\n", issue.TargetURL); err != nil {
+				return writeErr("write hreflang task target", err)
+			}
+		}
+		if issue.Detail != "" {
+			if _, err := fmt.Fprintf(w, "  - Detail: %s\n", issue.Detail); err != nil {
+				return writeErr("write hreflang task detail", err)
+			}
+		}
+
+		if i < len(issues)-1 {
+			if _, err := w.WriteString("\n"); err != nil {
+				return writeErr("write hreflang task separator", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+// clusterTopLinked is the number of most-linked members highlighted per
+// canonical cluster.
+const clusterTopLinked = 5
+
+// WriteCanonicalClusters creates a Markdown report at outputPath grouping
+// pages by canonical target. Each cluster lists the canonical URL and its
+// status, the most internally linked members, and every member page.
+func WriteCanonicalClusters(outputPath string, clusters []canonical.Cluster) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create canonical clusters output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create canonical clusters output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush canonical clusters file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close canonical clusters file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# Canonical Clusters\n\n"); err != nil {
+		return writeErr("write canonical clusters header", err)
+	}
+
+	if len(clusters) == 0 {
+		if _, err := w.WriteString("No pages canonicalize to another URL in this crawl.\n"); err != nil {
+			return writeErr("write no-canonical-clusters message", err)
+		}
+		return flushAndClose()
+	}
+
+	if _, err := w.WriteString("| Canonical URL | Status | Members |\n|---|---|---|\n"); err != nil {
+		return writeErr("write canonical clusters summary header", err)
+	}
+	for _, cluster := range clusters {
+		if _, err := fmt.Fprintf(w, "| `%s` | %s | %d |\n", cluster.CanonicalURL, clusterStatusLabel(cluster), len(cluster.Members)); err != nil {
+			return writeErr("write canonical clusters summary row", err)
+		}
+	}
+
+	for _, cluster := range clusters {
+		if _, err := fmt.Fprintf(w, "\n## `%s`\n\n", cluster.CanonicalURL); err != nil {
+			return writeErr("write canonical cluster heading", err)
+		}
+		if _, err := fmt.Fprintf(w, "- Status: %s\n- Members: %d\n", clusterStatusLabel(cluster), len(cluster.Members)); err != nil {
+			return writeErr("write canonical cluster summary", err)
+		}
+
+		if _, err := w.WriteString("\nMost linked members:\n\n"); err != nil {
+			return writeErr("write canonical cluster top heading", err)
+		}
+		for i, member := range cluster.Members {
+			if i >= clusterTopLinked {
+				break
+			}
+			if _, err := fmt.Fprintf(w, "%d. `%s` (%d inlinks)\n", i+1, member.URL, member.Inlinks); err != nil {
+				return writeErr("write canonical cluster top member", err)
+			}
+		}
+
+		if _, err := w.WriteString("\nAll members:\n\n"); err != nil {
+			return writeErr("write canonical cluster members heading", err)
+		}
+		for _, member := range cluster.Members {
+			if _, err := fmt.Fprintf(w, "- `%s`\n", member.URL); err != nil {
+				return writeErr("write canonical cluster member", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+func clusterStatusLabel(cluster canonical.Cluster) string {
+	switch {
+	case !cluster.StatusKnown:
+		return "not crawled"
+	case cluster.Status == 0:
+		return "request_failed"
+	default:
+		return strconv.Itoa(cluster.Status)
+	}
+}
+
+// lastmodSourceOrder is the order in which lastmod sources are summarized.
+var lastmodSourceOrder = []lastmod.Source{
+	lastmod.SourceJSONLD,
+	lastmod.SourceMicrodata,
+	lastmod.SourceMeta,
+	lastmod.SourceDublinCore,
+	lastmod.SourceTime,
+	lastmod.SourceHeader,
+	lastmod.SourceSitemap,
+	lastmod.SourceHistory,
+	lastmod.SourceFallback,
+}
+
+// WriteLastmodReport creates a Markdown report at outputPath summarizing where
+// lastmod values came from, followed by a checklist of pages whose sources
+// disagree or whose dates lie in the future.
+func WriteLastmodReport(outputPath string, report lastmod.Report) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create lastmod report output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create lastmod report output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush lastmod report file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close lastmod report file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# Lastmod Review Tasks\n\n"); err != nil {
+		return writeErr("write lastmod header", err)
+	}
+
+	if _, err := w.WriteString("| Source | Pages |\n|---|---|\n"); err != nil {
+		return writeErr("write lastmod source header", err)
+	}
+	for _, source := range lastmodSourceOrder {
+		if _, err := fmt.Fprintf(w, "| `%s` | %d |\n", source, report.BySource[source]); err != nil {
+			return writeErr("write lastmod source row", err)
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n%.1f%% of %d pages rely on the crawl-time fallback.\n\n", report.FallbackShare()*100, report.Total); err != nil {
+		return writeErr("write lastmod fallback share", err)
+	}
+
+	if len(report.Issues) == 0 {
+		if _, err := w.WriteString("No lastmod conflicts or future dates were found in this crawl.\n"); err != nil {
+			return writeErr("write no-lastmod-issues message", err)
+		}
+		return flushAndClose()
+	}
+
+	for i, issue := range report.Issues {
+		if _, err := fmt.Fprintf(w, "- [ ] Review lastmod on `%s`\n", issue.PageURL); err != nil {
+			return writeErr("write lastmod task item", err)
+		}
+		if _, err := fmt.Fprintf(w, "  - Type: `%s`\n", issue.Type); err != nil {
+			return writeErr("write lastmod task type", err)
+		}
+		if issue.Detail != "" {
+			if _, err := fmt.Fprintf(w, "  - Detail: %s\n", issue.Detail); err != nil {
+				return writeErr("write lastmod task detail", err)
+			}
+		}
+
+		if i < len(report.Issues)-1 {
+			if _, err := w.WriteString("\n"); err != nil {
+				return writeErr("write lastmod task separator", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+// WriteSitemapValidation creates a Markdown checklist at outputPath listing
+// every finding of a sitemap validation run, grouped under the sitemap file
+// it was found in.
+func WriteSitemapValidation(outputPath string, report sitemap.Report) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create sitemap validation output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create sitemap validation output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush sitemap validation file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close sitemap validation file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# Sitemap Validation Tasks\n\n"); err != nil {
+		return writeErr("write sitemap validation header", err)
+	}
+	if _, err := fmt.Fprintf(w, "Validated %d sitemap file(s) with %d URLs.\n\n", len(report.Sitemaps), report.URLCount); err != nil {
+		return writeErr("write sitemap validation summary", err)
+	}
+
+	if len(report.Issues) == 0 {
+		if _, err := w.WriteString("No sitemap issues were found.\n"); err != nil {
+			return writeErr("write no-sitemap-issues message", err)
+		}
+		return flushAndClose()
+	}
+
+	for i, issue := range report.Issues {
+		subject := issue.Sitemap
+		if issue.Loc != "" {
+			subject = issue.Loc
+		}
+		if _, err := fmt.Fprintf(w, "- [ ] Fix `%s`\n", subject); err != nil {
+			return writeErr("write sitemap validation task item", err)
+		}
+		if _, err := fmt.Fprintf(w, "  - Type: `%s`\n", issue.Type); err != nil {
+			return writeErr("write sitemap validation task type", err)
+		}
+		if issue.Loc != "" {
+			if _, err := fmt.Fprintf(w, "  - Sitemap: `%s`\n", issue.Sitemap); err != nil {
+				return writeErr("write sitemap validation task sitemap", err)
+			}
+		}
+		if issue.Detail != "" {
+			if _, err := fmt.Fprintf(w, "  - Detail: %s\n", issue.Detail); err != nil {
+				return writeErr("write sitemap validation task detail", err)
+			}
+		}
+
+		if i < len(report.Issues)-1 {
+			if _, err := w.WriteString("\n"); err != nil {
+				return writeErr("write sitemap validation task separator", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+// WriteSitemapDiff creates a Markdown report at outputPath describing how a
+// new sitemap differs from an old one: per-directory counts followed by the
+// added, removed and lastmod-changed URLs.
+func WriteSitemapDiff(outputPath string, diff sitemap.Diff) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create sitemap diff output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create sitemap diff output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush sitemap diff file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close sitemap diff file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# Sitemap Diff\n\n"); err != nil {
+		return writeErr("write sitemap diff header", err)
+	}
+	if _, err := fmt.Fprintf(w, "%d URLs before, %d after: %d added, %d removed, %d lastmod changes.\n",
+		diff.OldCount, diff.NewCount, len(diff.Added), len(diff.Removed), len(diff.Changed)); err != nil {
+		return writeErr("write sitemap diff summary", err)
+	}
+
+	if len(diff.Directories) == 0 {
+		if _, err := w.WriteString("\nThe sitemaps list the same URLs and lastmods.\n"); err != nil {
+			return writeErr("write no-sitemap-changes message", err)
+		}
+		return flushAndClose()
+	}
+
+	if _, err := w.WriteString("\n| Directory | Added | Removed | Lastmod changed |\n|---|---|---|---|\n"); err != nil {
+		return writeErr("write sitemap diff directory header", err)
+	}
+	for _, dir := range diff.Directories {
+		if _, err := fmt.Fprintf(w, "| `%s` | %d | %d | %d |\n", dir.Directory, dir.Added, dir.Removed, dir.Changed); err != nil {
+			return writeErr("write sitemap diff directory row", err)
+		}
+	}
+
+	sections := []struct {
+		title string
+		locs  []string
+	}{
+		{"Added", diff.Added},
+		{"Removed", diff.Removed},
+	}
+	for _, section := range sections {
+		if len(section.locs) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "\n## %s\n\n", section.title); err != nil {
+			return writeErr("write sitemap diff section header", err)
+		}
+		for _, loc := range section.locs {
+			if _, err := fmt.Fprintf(w, "- `%s`\n", loc); err != nil {
+				return writeErr("write sitemap diff entry", err)
+			}
+		}
+	}
+
+	if len(diff.Changed) > 0 {
+		if _, err := w.WriteString("\n## Lastmod changed\n\n"); err != nil {
+			return writeErr("write sitemap diff section header", err)
+		}
+		for _, change := range diff.Changed {
+			if _, err := fmt.Fprintf(w, "- `%s`: %s → %s\n", change.Loc, orNone(change.Old), orNone(change.New)); err != nil {
+				return writeErr("write sitemap diff lastmod change", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+// orNone renders an empty value as "(none)".
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// WriteRobotsReport creates a Markdown report at outputPath describing the
+// crawled site's robots.txt (directives, Sitemap lines, Crawl-delay and
+// ignored lines) and the internal links it blocked. Flagged blocks are
+// written as review tasks; the remaining blocked links are listed after
+// them.
+func WriteRobotsReport(outputPath string, file *robots.File, blocked []crawler.RobotsBlockedLink) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create robots report output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create robots report output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush robots report file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close robots report file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# robots.txt Report\n\n"); err != nil {
+		return writeErr("write robots report header", err)
+	}
+
+	if file == nil {
+		if _, err := w.WriteString("No robots.txt was found; every URL may be crawled.\n"); err != nil {
+			return writeErr("write missing-robots message", err)
+		}
+		return flushAndClose()
+	}
+
+	if _, err := w.WriteString("## Directives\n\n| Line | Directive | Value |\n|---|---|---|\n"); err != nil {
+		return writeErr("write robots directives header", err)
+	}
+	for _, d := range file.Directives {
+		if _, err := fmt.Fprintf(w, "| %d | `%s` | `%s` |\n", d.Line, d.Key, d.Value); err != nil {
+			return writeErr("write robots directive row", err)
+		}
+	}
+
+	if _, err := w.WriteString("\n## Sitemaps\n\n"); err != nil {
+		return writeErr("write robots sitemaps header", err)
+	}
+	if len(file.Sitemaps) == 0 {
+		if _, err := w.WriteString("No Sitemap lines.\n"); err != nil {
+			return writeErr("write no-sitemaps message", err)
+		}
+	}
+	for _, loc := range file.Sitemaps {
+		if _, err := fmt.Fprintf(w, "- `%s`\n", loc); err != nil {
+			return writeErr("write robots sitemap", err)
+		}
+	}
+
+	delays := make([]robots.Group, 0)
+	for _, group := range file.Groups {
+		if group.CrawlDelay > 0 {
+			delays = append(delays, group)
+		}
+	}
+	if len(delays) > 0 {
+		if _, err := w.WriteString("\n## Crawl-delay\n\n"); err != nil {
+			return writeErr("write robots crawl-delay header", err)
+		}
+		for _, group := range delays {
+			if _, err := fmt.Fprintf(w, "- `%s`: %s\n", strings.Join(group.Agents, "`, `"), group.CrawlDelay); err != nil {
+				return writeErr("write robots crawl-delay", err)
+			}
+		}
+	}
+
+	if len(file.Problems) > 0 {
+		if _, err := w.WriteString("\n## Ignored lines\n\n"); err != nil {
+			return writeErr("write robots problems header", err)
+		}
+		for _, problem := range file.Problems {
+			if _, err := fmt.Fprintf(w, "- [ ] Fix line %d\n  - Type: `%s`\n  - Detail: %s\n", problem.Line, problem.Type, problem.Detail); err != nil {
+				return writeErr("write robots problem", err)
+			}
+		}
+	}
+
+	if _, err := w.WriteString("\n## Blocked links\n\n"); err != nil {
+		return writeErr("write robots blocked header", err)
+	}
+	if len(blocked) == 0 {
+		if _, err := w.WriteString("robots.txt did not block any internal link found in this crawl.\n"); err != nil {
+			return writeErr("write no-blocked-links message", err)
+		}
+		return flushAndClose()
+	}
+
+	other := make([]crawler.RobotsBlockedLink, 0)
+	for _, link := range blocked {
+		if !link.Flagged {
+			other = append(other, link)
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "- [ ] Review robots.txt block on `%s`\n", link.URL); err != nil {
+			return writeErr("write robots blocked task item", err)
+		}
+		if link.Rule != "" {
+			if _, err := fmt.Fprintf(w, "  - Rule: %s\n", link.Rule); err != nil {
+				return writeErr("write robots blocked rule", err)
+			}
+		}
+		if link.CanonicalTarget {
+			if _, err := w.WriteString("  - Canonical target of crawled pages\n"); err != nil {
+				return writeErr("write robots blocked canonical target", err)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "  - Linked from %d page(s):\n", len(link.Sources)); err != nil {
+			return writeErr("write robots blocked sources header", err)
+		}
+		for _, source := range link.Sources {
+			if _, err := fmt.Fprintf(w, "    - `%s`\n", source); err != nil {
+				return writeErr("write robots blocked source", err)
+			}
+		}
+	}
+
+	if len(other) > 0 {
+		if len(other) < len(blocked) {
+			if _, err := w.WriteString("\n"); err != nil {
+				return writeErr("write robots blocked separator", err)
+			}
+		}
+		for _, link := range other {
+			rule := ""
+			if link.Rule != "" {
+				rule = "; " + link.Rule
+			}
+			if _, err := fmt.Fprintf(w, "- `%s` (linked from %d page(s)%s)\n", link.URL, len(link.Sources), rule); err != nil {
+				return writeErr("write robots blocked link", err)
+			}
+		}
+	}
+
+	return flushAndClose()
+}
+
+// WriteCertificateReport creates a Markdown report at outputPath listing the
+// TLS certificate of every HTTPS host requested during the crawl, with tasks
+// for certificates that have expired or expire within warnWithin of now.
+func WriteCertificateReport(outputPath string, certs []crawler.Certificate, warnWithin time.Duration, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("create certificate report output directory: %w", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create certificate report output file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+
+	flushAndClose := func() error {
+		if fErr := w.Flush(); fErr != nil {
+			_ = f.Close()
+			return fmt.Errorf("flush certificate report file: %w", fErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			return fmt.Errorf("close certificate report file: %w", cErr)
+		}
+		return nil
+	}
+
+	writeErr := func(msg string, err error) error {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if _, err := w.WriteString("# TLS Certificate Report\n\n"); err != nil {
+		return writeErr("write certificate report header", err)
+	}
+
+	if len(certs) == 0 {
+		if _, err := w.WriteString("No HTTPS hosts were requested in this crawl.\n"); err != nil {
+			return writeErr("write no-certificates message", err)
+		}
+		return flushAndClose()
+	}
+
+	expiring := 0
+	for _, cert := range certs {
+		remaining := cert.NotAfter.Sub(now)
+		if remaining >= warnWithin {
+			continue
+		}
+		expiring++
+
+		issueType, detail := "expiring_soon", fmt.Sprintf("expires on %s (in %d day(s))", cert.NotAfter.UTC().Format("2006-01-02"), int(remaining.Hours()/24))
+		if remaining <= 0 {
+			issueType, detail = "expired", fmt.Sprintf("expired on %s", cert.NotAfter.UTC().Format("2006-01-02"))
+		}
+		if _, err := fmt.Fprintf(w, "- [ ] Renew the certificate of `%s`\n  - Type: `%s`\n  - Detail: %s\n", cert.Host, issueType, detail); err != nil {
+			return writeErr("write certificate task item", err)
+		}
+	}
+	if expiring == 0 {
+		if _, err := fmt.Fprintf(w, "No certificate expires within %d day(s).\n", int(warnWithin.Hours()/24)); err != nil {
+			return writeErr("write no-expiring-certificates message", err)
+		}
+	}
+
+	if _, err := w.WriteString("\n## Certificates\n\n| Host | Issuer | Valid from | Expires | SANs |\n|---|---|---|---|---|\n"); err != nil {
+		return writeErr("write certificate table header", err)
+	}
+	for _, cert := range certs {
+		sans := "-"
+		if len(cert.DNSNames) > 0 {
+			sans = "`" + strings.Join(cert.DNSNames, "`, `") + "`"
+		}
+		if _, err := fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n", cert.Host, orNone(cert.Issuer),
+			cert.NotBefore.UTC().Format("2006-01-02"), cert.NotAfter.UTC().Format("2006-01-02"), sans); err != nil {
+			return writeErr("write certificate row", err)
+		}
+	}
+
+	return flushAndClose()
+}
@@ -9,6 +9,12 @@ import (
 
 	"github.com/tariktz/gopherseo/internal/canonical"
 	"github.com/tariktz/gopherseo/internal/crawler"
+	"github.com/tariktz/gopherseo/internal/hreflang"
+	"github.com/tariktz/gopherseo/internal/images"
+	"github.com/tariktz/gopherseo/internal/lastmod"
+	"github.com/tariktz/gopherseo/internal/media"
+	"github.com/tariktz/gopherseo/internal/robots"
+	"github.com/tariktz/gopherseo/internal/sitemap"
 )
 
 func TestWriteSitemap_BasicOutput(t *testing.T) {
@@ -200,6 +206,165 @@ func TestWriteSitemap_WithLastModified(t *testing.T) {
 	}
 }
 
+func TestWriteSitemapWithOptions_LastmodPrecision(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap.xml")
+
+	opts := SitemapOptions{
+		LastModified: map[string]time.Time{
+			"https://example.com/news": time.Date(2025, 6, 15, 8, 5, 9, 0, time.UTC),
+		},
+		LastmodPrecision: lastmod.PrecisionSecond,
+		Location:         time.FixedZone("CEST", 2*3600),
+	}
+	if err := WriteSitemapWithOptions(out, []string{"https://example.com/news"}, opts); err != nil {
+		t.Fatalf("WriteSitemapWithOptions: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), "<lastmod>2025-06-15T10:05:09+02:00</lastmod>") {
+		t.Errorf("unexpected lastmod:\n%s", data)
+	}
+}
+
+func TestWriteSitemapWithOptions_ChangefreqPriority(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap.xml")
+
+	opts := SitemapOptions{
+		LastModified: map[string]time.Time{"https://example.com/": time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+		Changefreq:   map[string]string{"https://example.com/": "daily"},
+		Priority:     map[string]float64{"https://example.com/": 1, "https://example.com/about": 0.5, "https://example.com/blog": 0.85},
+	}
+	if err := WriteSitemapWithOptions(out, []string{"https://example.com/", "https://example.com/about", "https://example.com/blog"}, opts); err != nil {
+		t.Fatalf("WriteSitemapWithOptions: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	body := string(data)
+	if !strings.Contains(body, "<lastmod>2025-06-15</lastmod>\n    <changefreq>daily</changefreq>\n    <priority>1.0</priority>") {
+		t.Errorf("expected lastmod, changefreq and priority in schema order:\n%s", body)
+	}
+	if !strings.Contains(body, "<loc>https://example.com/about</loc>\n    <priority>0.5</priority>") {
+		t.Errorf("expected priority without changefreq:\n%s", body)
+	}
+	if !strings.Contains(body, "<priority>0.85</priority>") {
+		t.Errorf("expected priority 0.85 to keep both decimals:\n%s", body)
+	}
+}
+
+func TestWriteSitemapWithOptions_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap.xml")
+
+	opts := SitemapOptions{
+		LastModified: map[string]time.Time{"https://example.com/": time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+		Changefreq:   map[string]string{"https://example.com/": "daily"},
+		Priority:     map[string]float64{"https://example.com/": 0.8},
+		Images:       map[string][]string{"https://example.com/": {"https://example.com/a.png"}},
+		Alternates:   map[string][]hreflang.Alternate{"https://example.com/": {{Hreflang: "de", URL: "https://example.com/de"}}},
+	}
+	if err := WriteSitemapWithOptions(out, []string{"https://example.com/", "https://example.com/de"}, opts); err != nil {
+		t.Fatalf("WriteSitemapWithOptions: %v", err)
+	}
+
+	urls, err := sitemap.Load(nil, out)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := sitemap.URL{Loc: "https://example.com/", LastMod: "2025-06-15", ChangeFreq: "daily", Priority: "0.8"}
+	if len(urls) != 2 || urls[0].Loc != want.Loc || urls[0].LastMod != want.LastMod ||
+		urls[0].ChangeFreq != want.ChangeFreq || urls[0].Priority != want.Priority {
+		t.Fatalf("read back %+v, want first entry %+v", urls, want)
+	}
+
+	report, err := sitemap.Validate(nil, out, sitemap.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(report.Issues) != 0 || report.URLCount != 2 {
+		t.Errorf("written sitemap should validate cleanly, got %d URLs and issues %+v", report.URLCount, report.Issues)
+	}
+}
+
+func TestWriteSitemap_NoChangefreqWithoutRules(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap.xml")
+
+	if err := WriteSitemap(out, []string{"https://example.com/"}, nil); err != nil {
+		t.Fatalf("WriteSitemap: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if strings.Contains(string(data), "changefreq") || strings.Contains(string(data), "priority") {
+		t.Errorf("unexpected changefreq/priority:\n%s", data)
+	}
+}
+
+func TestWriteSitemapWithOptions_Images(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap.xml")
+
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/product",
+	}
+
+	err := WriteSitemapWithOptions(out, urls, SitemapOptions{
+		Images: map[string][]string{
+			"https://example.com/product": {
+				"https://example.com/img/a.jpg",
+				"https://cdn.example.net/b.jpg",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteSitemapWithOptions: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, `xmlns:image="http://www.google.com/schemas/sitemap-image/1.1"`) {
+		t.Error("sitemap missing image namespace declaration")
+	}
+	if !strings.Contains(body, "<image:image>") || !strings.Contains(body, "<image:loc>https://cdn.example.net/b.jpg</image:loc>") {
+		t.Errorf("sitemap missing image entries:\n%s", body)
+	}
+	if strings.Count(body, "<image:image>") != 2 {
+		t.Errorf("expected 2 image entries, got %d", strings.Count(body, "<image:image>"))
+	}
+}
+
+func TestWriteSitemap_NoImageNamespaceWithoutImages(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap.xml")
+
+	if err := WriteSitemap(out, []string{"https://example.com/"}, nil); err != nil {
+		t.Fatalf("WriteSitemap: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	if strings.Contains(string(data), "xmlns:image") {
+		t.Error("image namespace should only be declared when images are present")
+	}
+}
+
 func TestWriteIssueTasks_InvalidPath(t *testing.T) {
 	dir := t.TempDir()
 	roDir := filepath.Join(dir, "readonly")
@@ -236,6 +401,113 @@ func TestWriteIssueTasks_SourceFallback(t *testing.T) {
 	}
 }
 
+func TestWriteIssueTasks_Soft404(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "issues.md")
+
+	tasks := []crawler.BrokenLinkTask{
+		{
+			URL:           "https://example.com/old-product",
+			Status:        200,
+			Sources:       []string{"https://example.com/"},
+			Soft404Reason: "content matches the site's error page",
+		},
+	}
+
+	if err := WriteIssueTasks(out, tasks); err != nil {
+		t.Fatalf("WriteIssueTasks: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, "(status: 200, soft_404)") {
+		t.Error("expected soft_404 status label")
+	}
+	if !strings.Contains(body, "Soft 404: content matches the site's error page") {
+		t.Error("expected soft-404 reason line")
+	}
+}
+
+func TestWriteIssueTasks_AttemptsAndError(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "tasks.md")
+
+	tasks := []crawler.BrokenLinkTask{
+		{
+			URL:      "https://example.com/slow",
+			Status:   0,
+			Sources:  []string{"https://example.com/"},
+			Attempts: 3,
+			Error:    "context deadline exceeded (Client.Timeout exceeded while awaiting headers)",
+		},
+		{URL: "https://example.com/gone", Status: 404, Attempts: 1, Error: "Not Found"},
+	}
+
+	if err := WriteIssueTasks(out, tasks); err != nil {
+		t.Fatalf("WriteIssueTasks: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, "  - Error: context deadline exceeded (Client.Timeout exceeded while awaiting headers)\n  - Attempts: 3\n") {
+		t.Errorf("expected error and attempts lines, got:\n%s", body)
+	}
+	if strings.Count(body, "Attempts:") != 1 {
+		t.Errorf("single attempts should not be listed, got:\n%s", body)
+	}
+}
+
+func TestWriteIssueTasks_GroupsByErrorClass(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "tasks.md")
+
+	tasks := []crawler.BrokenLinkTask{
+		{URL: "https://example.com/slow", Error: "i/o timeout", ErrorClass: crawler.ErrorTimeout},
+		{URL: "https://missing.example/", Error: "no such host", ErrorClass: crawler.ErrorDNS},
+		{URL: "https://example.com/gone", Status: 404, Error: "Not Found"},
+		{URL: "https://example.com/odd", Error: "unexpected failure"},
+	}
+
+	if err := WriteIssueTasks(out, tasks); err != nil {
+		t.Fatalf("WriteIssueTasks: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	headings := []string{
+		"## HTTP errors (1)",
+		"## DNS failures (1)",
+		"## Timeouts (1)",
+		"## Other request failures (1)",
+	}
+	last := -1
+	for _, h := range headings {
+		idx := strings.Index(body, h)
+		if idx < 0 {
+			t.Fatalf("missing heading %q, got:\n%s", h, body)
+		}
+		if idx < last {
+			t.Errorf("heading %q out of order, got:\n%s", h, body)
+		}
+		last = idx
+	}
+	if idx := strings.Index(body, "https://example.com/slow"); idx < strings.Index(body, "## Timeouts") {
+		t.Errorf("timeout task should be listed under its heading, got:\n%s", body)
+	}
+}
+
 func TestWriteCanonicalIssues_NoIssues(t *testing.T) {
 	dir := t.TempDir()
 	out := filepath.Join(dir, "canonical-issues.md")
@@ -288,3 +560,557 @@ func TestWriteCanonicalIssues_WithIssues(t *testing.T) {
 		t.Error("missing issue type")
 	}
 }
+
+func TestWriteImageReport(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "image-issues.md")
+
+	audits := []images.Audit{
+		{
+			URL:               "https://example.com/hero.jpg",
+			Pages:             []string{"https://example.com/"},
+			MissingAlt:        []string{"https://example.com/"},
+			MissingDimensions: []string{"https://example.com/"},
+			Status:            200,
+			ContentType:       "image/jpeg",
+			Size:              512000,
+			Oversize:          true,
+		},
+		{
+			URL:         "https://example.com/logo.png",
+			Pages:       []string{"https://example.com/"},
+			Status:      200,
+			ContentType: "image/png",
+			Size:        1000,
+		},
+		{
+			URL:         "https://example.com/divider.png",
+			Pages:       []string{"https://example.com/"},
+			EmptyAlt:    []string{"https://example.com/"},
+			Status:      200,
+			ContentType: "image/png",
+			Size:        100,
+		},
+	}
+
+	if err := WriteImageReport(out, audits); err != nil {
+		t.Fatalf("WriteImageReport: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, "# Image SEO Tasks") {
+		t.Error("missing image report header")
+	}
+	if !strings.Contains(body, "- [ ] Review image `https://example.com/hero.jpg` (status: 200, type: image/jpeg, size: 512000 bytes)") {
+		t.Error("missing image task item")
+	}
+	if !strings.Contains(body, "Oversize") || !strings.Contains(body, "Missing alt on") || !strings.Contains(body, "Missing width/height on") {
+		t.Error("missing image findings")
+	}
+	if strings.Contains(body, "logo.png") || strings.Contains(body, "divider.png") {
+		t.Error("images without issues, including decorative ones with an empty alt, should not be listed")
+	}
+}
+
+func TestWriteImageReport_NoIssues(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "image-issues.md")
+
+	if err := WriteImageReport(out, nil); err != nil {
+		t.Fatalf("WriteImageReport: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	if !strings.Contains(string(data), "No image issues") {
+		t.Error("expected no-image-issues message")
+	}
+}
+
+func TestWriteVideoSitemap(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "video-sitemap.xml")
+
+	videos := map[string][]media.Video{
+		"https://example.com/launch": {
+			{
+				ThumbnailURL: "https://example.com/launch.jpg",
+				Title:        "Launch",
+				Description:  "Product launch",
+				ContentURL:   "https://example.com/launch.mp4",
+				Duration:     90 * time.Second,
+			},
+			{ContentURL: "https://example.com/incomplete.mp4"},
+		},
+		"https://example.com/empty": {
+			{ContentURL: "https://example.com/other.mp4"},
+		},
+	}
+
+	if err := WriteVideoSitemap(out, videos); err != nil {
+		t.Fatalf("WriteVideoSitemap: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, `xmlns:video="http://www.google.com/schemas/sitemap-video/1.1"`) {
+		t.Error("missing video namespace")
+	}
+	if strings.Count(body, "<video:video>") != 1 {
+		t.Errorf("expected exactly one complete video entry:\n%s", body)
+	}
+	if !strings.Contains(body, "<video:duration>90</video:duration>") {
+		t.Error("missing video duration in seconds")
+	}
+	if strings.Contains(body, "https://example.com/empty") {
+		t.Error("pages without complete videos should be omitted")
+	}
+}
+
+func TestWriteNewsSitemap(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "news-sitemap.xml")
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	news := map[string]media.NewsArticle{
+		"https://example.com/fresh": {
+			Title: "Fresh", PublicationName: "Daily", Language: "en",
+			PublicationDate: now.Add(-2 * time.Hour),
+		},
+		"https://example.com/stale": {
+			Title: "Stale", PublicationName: "Daily", Language: "en",
+			PublicationDate: now.Add(-72 * time.Hour),
+		},
+		"https://example.com/incomplete": {
+			Title: "Incomplete", PublicationDate: now.Add(-time.Hour),
+		},
+	}
+
+	if err := WriteNewsSitemap(out, news, now); err != nil {
+		t.Fatalf("WriteNewsSitemap: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, `xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"`) {
+		t.Error("missing news namespace")
+	}
+	if !strings.Contains(body, "https://example.com/fresh") || !strings.Contains(body, "<news:name>Daily</news:name>") {
+		t.Errorf("missing fresh article:\n%s", body)
+	}
+	if strings.Contains(body, "stale") || strings.Contains(body, "incomplete") {
+		t.Error("stale and incomplete articles should be omitted")
+	}
+}
+
+func TestWriteMediaIssues(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "media-issues.md")
+
+	issues := []media.Issue{
+		{PageURL: "https://example.com/v", Kind: media.KindVideo, Subject: "https://example.com/v.mp4", Missing: []string{"thumbnail_loc", "description"}},
+	}
+
+	if err := WriteMediaIssues(out, issues); err != nil {
+		t.Fatalf("WriteMediaIssues: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, "- [ ] Complete video metadata on `https://example.com/v`") {
+		t.Error("missing media task item")
+	}
+	if !strings.Contains(body, "Missing: thumbnail_loc, description") {
+		t.Error("missing media task fields")
+	}
+}
+
+func TestWriteSitemapWithOptions_Alternates(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap.xml")
+
+	alternates := []hreflang.Alternate{
+		{Hreflang: "en", URL: "https://example.com/en"},
+		{Hreflang: "de", URL: "https://example.com/de"},
+	}
+
+	err := WriteSitemapWithOptions(out, []string{"https://example.com/en", "https://example.com/de"}, SitemapOptions{
+		Alternates: map[string][]hreflang.Alternate{
+			"https://example.com/en": alternates,
+			"https://example.com/de": alternates,
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteSitemapWithOptions: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, `xmlns:xhtml="http://www.w3.org/1999/xhtml"`) {
+		t.Error("missing xhtml namespace")
+	}
+	if strings.Count(body, `<xhtml:link rel="alternate" hreflang="de" href="https://example.com/de">`) != 2 {
+		t.Errorf("expected de alternate on both URLs:\n%s", body)
+	}
+}
+
+func TestWriteHreflangIssues(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "hreflang-issues.md")
+
+	issues := []hreflang.Issue{
+		{PageURL: "https://example.com/en", Hreflang: "en-UK", TargetURL: "https://example.com/uk", Type: hreflang.IssueInvalidCode, Detail: "invalid"},
+	}
+
+	if err := WriteHreflangIssues(out, issues); err != nil {
+		t.Fatalf("WriteHreflangIssues: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	if !strings.Contains(body, "# Hreflang Cleanup Tasks") || !strings.Contains(body, "`invalid_code`") || !strings.Contains(body, "Hreflang: `en-UK`") {
+		t.Errorf("unexpected hreflang report:\n%s", body)
+	}
+}
+
+func TestWriteCanonicalClusters(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "canonical-clusters.md")
+
+	clusters := []canonical.Cluster{
+		{
+			CanonicalURL: "https://example.com/list",
+			Status:       200,
+			StatusKnown:  true,
+			Members: []canonical.Member{
+				{URL: "https://example.com/list", Inlinks: 12},
+				{URL: "https://example.com/list?page=2", Inlinks: 3},
+			},
+		},
+		{
+			CanonicalURL: "https://example.com/missing",
+			Members:      []canonical.Member{{URL: "https://example.com/old"}},
+		},
+	}
+
+	if err := WriteCanonicalClusters(out, clusters); err != nil {
+		t.Fatalf("WriteCanonicalClusters: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	for _, want := range []string{
+		"# Canonical Clusters",
+		"| `https://example.com/list` | 200 | 2 |",
+		"1. `https://example.com/list` (12 inlinks)",
+		"2. `https://example.com/list?page=2` (3 inlinks)",
+		"- `https://example.com/list?page=2`",
+		"- Status: not crawled",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("report missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWriteCanonicalClusters_Empty(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "canonical-clusters.md")
+
+	if err := WriteCanonicalClusters(out, nil); err != nil {
+		t.Fatalf("WriteCanonicalClusters: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), "No pages canonicalize to another URL") {
+		t.Errorf("unexpected empty report:\n%s", data)
+	}
+}
+
+func TestWriteLastmodReport(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "lastmod-issues.md")
+
+	report := lastmod.Report{
+		Total:    4,
+		BySource: map[lastmod.Source]int{lastmod.SourceJSONLD: 3, lastmod.SourceFallback: 1},
+		Issues: []lastmod.Issue{
+			{PageURL: "https://example.com/a", Type: lastmod.IssueFutureDate, Detail: "jsonld dateModified is in the future"},
+		},
+	}
+
+	if err := WriteLastmodReport(out, report); err != nil {
+		t.Fatalf("WriteLastmodReport: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	for _, want := range []string{
+		"# Lastmod Review Tasks",
+		"| `jsonld` | 3 |",
+		"| `fallback` | 1 |",
+		"25.0% of 4 pages rely on the crawl-time fallback.",
+		"- [ ] Review lastmod on `https://example.com/a`",
+		"  - Type: `future_date`",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("report missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWriteSitemapValidation(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap-validation.md")
+
+	report := sitemap.Report{
+		Sitemaps: []string{"https://example.com/sitemap.xml"},
+		URLCount: 2,
+		Issues: []sitemap.Issue{
+			{Sitemap: "https://example.com/sitemap.xml", Type: sitemap.IssueWrongNamespace, Detail: "root namespace is \"\""},
+			{Sitemap: "https://example.com/sitemap.xml", Loc: "https://example.com/a", Type: sitemap.IssueInvalidLastmod, Detail: "lastmod \"x\" is not a W3C datetime"},
+		},
+	}
+
+	if err := WriteSitemapValidation(out, report); err != nil {
+		t.Fatalf("WriteSitemapValidation: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	for _, want := range []string{
+		"# Sitemap Validation Tasks",
+		"Validated 1 sitemap file(s) with 2 URLs.",
+		"- [ ] Fix `https://example.com/sitemap.xml`\n  - Type: `wrong_namespace`",
+		"- [ ] Fix `https://example.com/a`\n  - Type: `invalid_lastmod`\n  - Sitemap: `https://example.com/sitemap.xml`",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("report missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWriteSitemapValidation_NoIssues(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap-validation.md")
+
+	if err := WriteSitemapValidation(out, sitemap.Report{Sitemaps: []string{"sitemap.xml"}}); err != nil {
+		t.Fatalf("WriteSitemapValidation: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), "No sitemap issues were found.") {
+		t.Errorf("expected empty-state message, got:\n%s", data)
+	}
+}
+
+func TestWriteSitemapDiff(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap-diff.md")
+
+	diff := sitemap.Diff{
+		OldCount: 2,
+		NewCount: 2,
+		Added:    []string{"https://example.com/blog/new"},
+		Removed:  []string{"https://example.com/blog/old"},
+		Changed:  []sitemap.LastmodChange{{Loc: "https://example.com/", Old: "", New: "2025-01-02"}},
+		Directories: []sitemap.DirectoryCount{
+			{Directory: "/", Changed: 1},
+			{Directory: "/blog/", Added: 1, Removed: 1},
+		},
+	}
+
+	if err := WriteSitemapDiff(out, diff); err != nil {
+		t.Fatalf("WriteSitemapDiff: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	for _, want := range []string{
+		"# Sitemap Diff",
+		"2 URLs before, 2 after: 1 added, 1 removed, 1 lastmod changes.",
+		"| `/blog/` | 1 | 1 | 0 |",
+		"## Added\n\n- `https://example.com/blog/new`",
+		"## Removed\n\n- `https://example.com/blog/old`",
+		"- `https://example.com/`: (none) → 2025-01-02",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("report missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWriteSitemapDiff_NoChanges(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sitemap-diff.md")
+
+	if err := WriteSitemapDiff(out, sitemap.Diff{OldCount: 3, NewCount: 3}); err != nil {
+		t.Fatalf("WriteSitemapDiff: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), "The sitemaps list the same URLs and lastmods.") {
+		t.Errorf("expected empty-state message, got:\n%s", data)
+	}
+}
+
+func TestWriteRobotsReport(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "robots-report.md")
+
+	file, err := robots.Parse(strings.NewReader("User-agent: *\nDisallow: /private\nCrawl-delay: 2\nNoindex: /x\nSitemap: https://example.com/sitemap.xml\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	blocked := []crawler.RobotsBlockedLink{
+		{URL: "https://example.com/private/a", Sources: []string{"https://example.com/"}, Rule: "line 2: Disallow: /private", CanonicalTarget: true, Flagged: true},
+		{URL: "https://example.com/private/b", Sources: []string{"https://example.com/"}, Rule: "line 2: Disallow: /private"},
+	}
+
+	if err := WriteRobotsReport(out, file, blocked); err != nil {
+		t.Fatalf("WriteRobotsReport: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	for _, want := range []string{
+		"# robots.txt Report",
+		"| 2 | `disallow` | `/private` |",
+		"- `https://example.com/sitemap.xml`",
+		"- `*`: 2s",
+		"- [ ] Fix line 4\n  - Type: `unknown_directive`",
+		"- [ ] Review robots.txt block on `https://example.com/private/a`\n  - Rule: line 2: Disallow: /private\n  - Canonical target of crawled pages\n  - Linked from 1 page(s):",
+		"- `https://example.com/private/b` (linked from 1 page(s); line 2: Disallow: /private)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("report missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWriteRobotsReport_NoRobots(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "robots-report.md")
+
+	if err := WriteRobotsReport(out, nil, nil); err != nil {
+		t.Fatalf("WriteRobotsReport: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), "No robots.txt was found") {
+		t.Errorf("expected missing-robots message, got:\n%s", data)
+	}
+}
+
+func TestWriteCertificateReport(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "tls-report.md")
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	certs := []crawler.Certificate{
+		{Host: "cdn.example.com", Issuer: "CN=R11,O=Let's Encrypt,C=US", NotBefore: now.AddDate(0, -2, 0), NotAfter: now.AddDate(0, 0, 10), DNSNames: []string{"cdn.example.com"}},
+		{Host: "old.example.com", Issuer: "CN=Internal CA", NotBefore: now.AddDate(-1, 0, 0), NotAfter: now.AddDate(0, 0, -1)},
+		{Host: "www.example.com", Issuer: "CN=R11,O=Let's Encrypt,C=US", NotBefore: now, NotAfter: now.AddDate(0, 3, 0), DNSNames: []string{"example.com", "www.example.com"}},
+	}
+
+	if err := WriteCertificateReport(out, certs, 30*24*time.Hour, now); err != nil {
+		t.Fatalf("WriteCertificateReport: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	body := string(data)
+	for _, want := range []string{
+		"- [ ] Renew the certificate of `cdn.example.com`\n  - Type: `expiring_soon`\n  - Detail: expires on 2026-10-28 (in 10 day(s))\n",
+		"- [ ] Renew the certificate of `old.example.com`\n  - Type: `expired`\n  - Detail: expired on 2026-10-17\n",
+		"| `www.example.com` | CN=R11,O=Let's Encrypt,C=US | 2026-10-18 | 2027-01-18 | `example.com`, `www.example.com` |\n",
+		"| `old.example.com` | CN=Internal CA | 2025-10-18 | 2026-10-17 | - |\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in report, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "certificate of `www.example.com`") {
+		t.Errorf("valid certificate should not get a task, got:\n%s", body)
+	}
+}
+
+func TestWriteCertificateReport_NoCertificates(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "tls-report.md")
+
+	if err := WriteCertificateReport(out, nil, 30*24*time.Hour, time.Now()); err != nil {
+		t.Fatalf("WriteCertificateReport: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), "No HTTPS hosts were requested") {
+		t.Errorf("expected no-certificates message, got:\n%s", data)
+	}
+}
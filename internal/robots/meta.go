@@ -0,0 +1,57 @@
+package robots
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// IsNoindex reports whether a page opts out of indexing through a
+// <meta name="robots|googlebot"> tag or the X-Robots-Tag header. The "none"
+// directive implies noindex.
+func IsNoindex(header http.Header, doc *goquery.Document) bool {
+	if header != nil {
+		for _, value := range header.Values("X-Robots-Tag") {
+			// Values may be scoped to a crawler, e.g. "googlebot: noindex".
+			if agent, directives, ok := strings.Cut(value, ":"); ok && !strings.Contains(agent, ",") {
+				agent = strings.ToLower(strings.TrimSpace(agent))
+				if agent != "googlebot" && agent != "robots" && agent != "*" {
+					continue
+				}
+				value = directives
+			}
+			if hasNoindex(value) {
+				return true
+			}
+		}
+	}
+
+	if doc == nil {
+		return false
+	}
+
+	noindex := false
+	doc.Find("meta[name]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		name := strings.ToLower(strings.TrimSpace(s.AttrOr("name", "")))
+		if name != "robots" && name != "googlebot" {
+			return true
+		}
+		if hasNoindex(s.AttrOr("content", "")) {
+			noindex = true
+			return false
+		}
+		return true
+	})
+	return noindex
+}
+
+func hasNoindex(directives string) bool {
+	for _, directive := range strings.Split(directives, ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "noindex", "none":
+			return true
+		}
+	}
+	return false
+}
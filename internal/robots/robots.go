@@ -0,0 +1,361 @@
+// Package robots parses robots.txt files and evaluates whether URLs may be
+// crawled, and detects noindex directives in robots meta tags and the
+// X-Robots-Tag header.
+//
+// Rule matching follows Google's precedence: the most specific user-agent
+// group applies, and within it the longest matching rule wins, with Allow
+// winning ties.
+package robots
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a single Allow or Disallow line.
+type Rule struct {
+	Allow bool
+	// Pattern is the path pattern as written, supporting * and a trailing $.
+	Pattern string
+	// Line is the 1-based line number of the rule in robots.txt.
+	Line int
+
+	re *regexp.Regexp
+}
+
+// String renders the rule as written in robots.txt, e.g. "Disallow: /tmp".
+func (r Rule) String() string {
+	if r.Allow {
+		return "Allow: " + r.Pattern
+	}
+	return "Disallow: " + r.Pattern
+}
+
+// Group is a set of rules shared by one or more user agents.
+type Group struct {
+	// Agents holds the lower-cased user-agent tokens of the group.
+	Agents []string
+	Rules  []Rule
+	// CrawlDelay is the group's Crawl-delay, zero when not set.
+	CrawlDelay time.Duration
+	// Line is the line number of the group's first User-agent line.
+	Line int
+}
+
+// Directive is a recognized "key: value" line of robots.txt.
+type Directive struct {
+	Line int
+	// Key is the lower-cased directive name, e.g. "disallow".
+	Key   string
+	Value string
+}
+
+// ProblemType describes a robots.txt parsing problem category.
+type ProblemType string
+
+const (
+	// ProblemSyntax is a line that is not a "key: value" pair, a rule
+	// outside any User-agent group, or a value that cannot be parsed.
+	ProblemSyntax ProblemType = "syntax_error"
+	// ProblemUnknownDirective is a "key: value" line with a key crawlers do
+	// not support.
+	ProblemUnknownDirective ProblemType = "unknown_directive"
+)
+
+// Problem is a line of robots.txt that crawlers ignore.
+type Problem struct {
+	Line   int
+	Type   ProblemType
+	Detail string
+}
+
+// File is a parsed robots.txt.
+type File struct {
+	Groups []Group
+	// Directives lists every recognized line in file order.
+	Directives []Directive
+	// Sitemaps holds the URLs of all Sitemap lines.
+	Sitemaps []string
+	// Problems lists lines that were ignored while parsing.
+	Problems []Problem
+}
+
+// Parse reads a robots.txt document. Lines that cannot be understood are
+// ignored, as crawlers do, and recorded in File.Problems.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+	var current *Group
+	lastWasAgent := false
+	lineNo := 0
+
+	problem := func(problemType ProblemType, format string, args ...any) {
+		f.Problems = append(f.Problems, Problem{Line: lineNo, Type: problemType, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			problem(ProblemSyntax, "line %q is not a \"key: value\" pair", strings.TrimSpace(line))
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent", "allow", "disallow", "sitemap", "crawl-delay":
+			f.Directives = append(f.Directives, Directive{Line: lineNo, Key: key, Value: value})
+		}
+
+		switch key {
+		case "user-agent":
+			if current == nil || !lastWasAgent {
+				f.Groups = append(f.Groups, Group{Line: lineNo})
+				current = &f.Groups[len(f.Groups)-1]
+			}
+			current.Agents = append(current.Agents, strings.ToLower(value))
+			lastWasAgent = true
+		case "allow", "disallow":
+			lastWasAgent = false
+			if current == nil {
+				problem(ProblemSyntax, "%s before any User-agent line", key)
+				continue
+			}
+			// An empty Disallow allows everything and is equivalent to no rule.
+			if value == "" {
+				continue
+			}
+			current.Rules = append(current.Rules, Rule{
+				Allow:   key == "allow",
+				Pattern: value,
+				Line:    lineNo,
+				re:      compilePattern(value),
+			})
+		case "sitemap":
+			// Sitemap lines are independent of groups.
+			if parsed, err := url.Parse(value); err != nil || !parsed.IsAbs() {
+				problem(ProblemSyntax, "sitemap %q is not an absolute URL", value)
+				continue
+			}
+			f.Sitemaps = append(f.Sitemaps, value)
+		case "crawl-delay":
+			lastWasAgent = false
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds < 0 {
+				problem(ProblemSyntax, "crawl-delay %q is not a number of seconds", value)
+				continue
+			}
+			if current == nil {
+				problem(ProblemSyntax, "crawl-delay before any User-agent line")
+				continue
+			}
+			current.CrawlDelay = time.Duration(seconds * float64(time.Second))
+		default:
+			lastWasAgent = false
+			problem(ProblemUnknownDirective, "unknown directive %q", key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read robots.txt: %w", err)
+	}
+
+	return f, nil
+}
+
+// Load reads and parses the robots.txt at location, a file path or an
+// http(s) URL. Any response other than 200 is an error.
+func Load(client *http.Client, location string, userAgent string) (*File, error) {
+	lower := strings.ToLower(location)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		file, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("open robots.txt: %w", err)
+		}
+		defer file.Close()
+		return Parse(file)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build robots.txt request: %w", err)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch robots.txt: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch robots.txt: %s responded with %d", location, resp.StatusCode)
+	}
+	return Parse(resp.Body)
+}
+
+// CrawlDelay returns the Crawl-delay of the group that applies to userAgent,
+// zero when none is set. A nil File has no delay.
+func (f *File) CrawlDelay(userAgent string) time.Duration {
+	if f == nil {
+		return 0
+	}
+	var delay time.Duration
+	for _, group := range f.groupsFor(userAgent) {
+		if group.CrawlDelay > delay {
+			delay = group.CrawlDelay
+		}
+	}
+	return delay
+}
+
+// Allowed reports whether userAgent may crawl rawURL. A nil File allows
+// everything.
+func (f *File) Allowed(userAgent, rawURL string) bool {
+	return f.Match(userAgent, rawURL).Allowed
+}
+
+// Match is the outcome of evaluating a URL against robots.txt.
+type Match struct {
+	Allowed bool
+	// Rule is the rule that decided, nil when no rule matched.
+	Rule *Rule
+	// Agents lists the user-agent tokens of the groups that applied, empty
+	// when no group applies to the user agent.
+	Agents []string
+}
+
+// Match evaluates rawURL for userAgent and returns the decision together
+// with the rule that made it. Within the applicable groups the longest
+// matching pattern wins and Allow wins ties. A nil File allows everything.
+func (f *File) Match(userAgent, rawURL string) Match {
+	result := Match{Allowed: true}
+	if f == nil {
+		return result
+	}
+
+	path := requestPath(rawURL)
+	if path == "/robots.txt" {
+		return result
+	}
+
+	best := -1
+	for _, group := range f.groupsFor(userAgent) {
+		result.Agents = append(result.Agents, group.Agents...)
+		for i := range group.Rules {
+			rule := &group.Rules[i]
+			if !rule.re.MatchString(path) {
+				continue
+			}
+			length := len(rule.Pattern)
+			if length > best || (length == best && rule.Allow) {
+				best = length
+				result.Allowed = rule.Allow
+				result.Rule = rule
+			}
+		}
+	}
+	return result
+}
+
+// groupsFor returns the groups that apply to userAgent: every group naming
+// the most specific matching agent token, or the "*" groups otherwise.
+func (f *File) groupsFor(userAgent string) []Group {
+	token := productToken(userAgent)
+
+	bestLen := 0
+	var matched []Group
+	for _, group := range f.Groups {
+		for _, agent := range group.Agents {
+			if agent == "*" || agent == "" || !strings.HasPrefix(token, agent) {
+				continue
+			}
+			switch {
+			case len(agent) > bestLen:
+				bestLen = len(agent)
+				matched = []Group{group}
+			case len(agent) == bestLen:
+				matched = append(matched, group)
+			}
+			break
+		}
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+
+	for _, group := range f.Groups {
+		for _, agent := range group.Agents {
+			if agent == "*" {
+				matched = append(matched, group)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// productToken extracts the lower-cased product name from a User-Agent
+// string, e.g. "GopherSEO-Bot/1.0" -> "gopherseo-bot".
+func productToken(userAgent string) string {
+	token := strings.TrimSpace(userAgent)
+	if i := strings.IndexAny(token, "/ ;("); i >= 0 {
+		token = token[:i]
+	}
+	return strings.ToLower(token)
+}
+
+// requestPath returns the path and query of rawURL as matched by robots
+// rules.
+func requestPath(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	path := parsed.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if parsed.RawQuery != "" {
+		path += "?" + parsed.RawQuery
+	}
+	return path
+}
+
+// compilePattern converts a robots.txt path pattern into an anchored regular
+// expression: * matches any sequence and a trailing $ anchors the end.
+func compilePattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
@@ -0,0 +1,237 @@
+package robots
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func mustParse(t *testing.T, body string) *File {
+	t.Helper()
+	f, err := Parse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return f
+}
+
+func TestAllowed_LongestMatchWins(t *testing.T) {
+	f := mustParse(t, `
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow: /search?
+`)
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/", true},
+		{"https://example.com/private/secret", false},
+		{"https://example.com/private/public/page", true},
+		{"https://example.com/docs/file.pdf", false},
+		{"https://example.com/docs/file.pdf?x=1", true},
+		{"https://example.com/search?q=shoes", false},
+		{"https://example.com/robots.txt", true},
+	}
+
+	for _, tt := range tests {
+		if got := f.Allowed("GopherSEO-Bot/1.0", tt.url); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestAllowed_TieGoesToAllow(t *testing.T) {
+	f := mustParse(t, "User-agent: *\nDisallow: /page\nAllow: /page\n")
+
+	if !f.Allowed("bot", "https://example.com/page") {
+		t.Error("equal-length allow and disallow should allow")
+	}
+}
+
+func TestAllowed_MostSpecificAgentGroup(t *testing.T) {
+	f := mustParse(t, `
+User-agent: *
+Disallow: /
+
+User-agent: gopherseo-bot
+User-agent: otherbot
+Disallow: /admin
+`)
+
+	if !f.Allowed("GopherSEO-Bot/1.0", "https://example.com/page") {
+		t.Error("specific group should replace the * group")
+	}
+	if f.Allowed("GopherSEO-Bot/1.0", "https://example.com/admin") {
+		t.Error("specific group disallow should apply")
+	}
+	if f.Allowed("SomeCrawler/2.0", "https://example.com/page") {
+		t.Error("unmatched agent should fall back to the * group")
+	}
+}
+
+func TestAllowed_NilFileAndEmptyDisallow(t *testing.T) {
+	var nilFile *File
+	if !nilFile.Allowed("bot", "https://example.com/anything") {
+		t.Error("nil file should allow everything")
+	}
+
+	f := mustParse(t, "User-agent: *\nDisallow:\n")
+	if !f.Allowed("bot", "https://example.com/anything") {
+		t.Error("empty Disallow should allow everything")
+	}
+}
+
+func TestParse_DirectivesAndProblems(t *testing.T) {
+	f := mustParse(t, `# comment
+Allow: /early
+User-agent: *
+Disallow: /private
+Crawl-delay: 2.5
+Host: example.com
+this line is broken
+Crawl-delay: soon
+
+Sitemap: https://example.com/sitemap.xml
+Sitemap: /relative.xml
+`)
+
+	if len(f.Groups) != 1 || f.Groups[0].Line != 3 {
+		t.Fatalf("unexpected groups: %+v", f.Groups)
+	}
+	if rule := f.Groups[0].Rules[0]; rule.Line != 4 || rule.Pattern != "/private" {
+		t.Errorf("rule=%+v, want /private on line 4", rule)
+	}
+	if got := f.CrawlDelay("GopherSEO-Bot/1.0"); got != 2500*time.Millisecond {
+		t.Errorf("CrawlDelay=%v, want 2.5s", got)
+	}
+	if len(f.Sitemaps) != 1 || f.Sitemaps[0] != "https://example.com/sitemap.xml" {
+		t.Errorf("Sitemaps=%v", f.Sitemaps)
+	}
+	if len(f.Directives) != 7 {
+		t.Errorf("expected 7 directives, got %+v", f.Directives)
+	}
+
+	want := []Problem{
+		{Line: 2, Type: ProblemSyntax},
+		{Line: 6, Type: ProblemUnknownDirective},
+		{Line: 7, Type: ProblemSyntax},
+		{Line: 8, Type: ProblemSyntax},
+		{Line: 11, Type: ProblemSyntax},
+	}
+	if len(f.Problems) != len(want) {
+		t.Fatalf("expected %d problems, got %+v", len(want), f.Problems)
+	}
+	for i, p := range want {
+		if f.Problems[i].Line != p.Line || f.Problems[i].Type != p.Type {
+			t.Errorf("problem %d = %+v, want line %d %s", i, f.Problems[i], p.Line, p.Type)
+		}
+	}
+}
+
+func TestCrawlDelay_NilFile(t *testing.T) {
+	var f *File
+	if got := f.CrawlDelay("bot"); got != 0 {
+		t.Errorf("CrawlDelay on nil file = %v, want 0", got)
+	}
+}
+
+func TestMatch_ReportsDecidingRule(t *testing.T) {
+	f := mustParse(t, `User-agent: *
+Disallow: /
+
+User-agent: Googlebot
+Disallow: /private
+Allow: /private/public
+`)
+
+	m := f.Match("Googlebot", "https://example.com/private/public/page")
+	if !m.Allowed || m.Rule == nil || m.Rule.Line != 6 || m.Rule.Pattern != "/private/public" {
+		t.Errorf("Match = %+v, want allowed by line 6", m)
+	}
+	if len(m.Agents) != 1 || m.Agents[0] != "googlebot" {
+		t.Errorf("Agents = %v, want [googlebot]", m.Agents)
+	}
+
+	m = f.Match("Googlebot", "/about")
+	if !m.Allowed || m.Rule != nil {
+		t.Errorf("Match(/about) = %+v, want allowed without rule", m)
+	}
+
+	m = f.Match("OtherBot", "/about")
+	if m.Allowed || m.Rule == nil || m.Rule.Line != 2 {
+		t.Fatalf("Match(OtherBot) = %+v, want disallowed by line 2", m)
+	}
+	if got := m.Rule.String(); got != "Disallow: /" {
+		t.Errorf("Rule.String() = %q, want %q", got, "Disallow: /")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "robots.txt")
+	if err := os.WriteFile(path, []byte("User-agent: *\nDisallow: /x\n"), 0o644); err != nil {
+		t.Fatalf("write robots.txt: %v", err)
+	}
+	f, err := Load(nil, path, "")
+	if err != nil || f.Allowed("bot", "/x") {
+		t.Fatalf("Load(file) = %+v, %v", f, err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /y\n"))
+	}))
+	defer srv.Close()
+
+	f, err = Load(srv.Client(), srv.URL+"/robots.txt", "bot")
+	if err != nil || f.Allowed("bot", "/y") {
+		t.Fatalf("Load(url) = %+v, %v", f, err)
+	}
+	if _, err := Load(srv.Client(), srv.URL+"/missing.txt", "bot"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+func TestIsNoindex(t *testing.T) {
+	doc := func(html string) *goquery.Document {
+		d, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			t.Fatalf("build document: %v", err)
+		}
+		return d
+	}
+
+	if !IsNoindex(nil, doc(`<html><head><meta name="robots" content="noindex, follow"></head></html>`)) {
+		t.Error("meta robots noindex should be detected")
+	}
+	if !IsNoindex(nil, doc(`<html><head><meta name="googlebot" content="none"></head></html>`)) {
+		t.Error("meta googlebot none should be detected")
+	}
+	if IsNoindex(nil, doc(`<html><head><meta name="robots" content="index, follow"></head></html>`)) {
+		t.Error("index,follow should not be noindex")
+	}
+
+	header := http.Header{}
+	header.Set("X-Robots-Tag", "noindex")
+	if !IsNoindex(header, nil) {
+		t.Error("X-Robots-Tag noindex should be detected")
+	}
+
+	scoped := http.Header{}
+	scoped.Set("X-Robots-Tag", "otherbot: noindex")
+	if IsNoindex(scoped, nil) {
+		t.Error("X-Robots-Tag scoped to another crawler should be ignored")
+	}
+}
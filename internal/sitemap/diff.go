@@ -0,0 +1,126 @@
+package sitemap
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/tariktz/gopherseo/internal/lastmod"
+)
+
+// LastmodChange is a URL present in both sitemaps whose <lastmod> differs.
+type LastmodChange struct {
+	Loc string
+	Old string
+	New string
+}
+
+// DirectoryCount summarizes the changes under one top-level directory.
+type DirectoryCount struct {
+	// Directory is the first path segment with slashes ("/blog/"), or "/"
+	// for URLs directly under the root.
+	Directory string
+	Added     int
+	Removed   int
+	Changed   int
+}
+
+// Diff describes how a new sitemap differs from an old one.
+type Diff struct {
+	OldCount int
+	NewCount int
+	Added    []string
+	Removed  []string
+	Changed  []LastmodChange
+	// Directories is sorted by directory and lists only directories with at
+	// least one change.
+	Directories []DirectoryCount
+}
+
+// Compare diffs two URL lists, as returned by Load. Lastmod values that parse
+// to the same instant (e.g. "2025-01-02" and "2025-01-02T00:00:00Z") are
+// considered equal. Results are sorted by loc.
+func Compare(oldURLs, newURLs []URL) Diff {
+	oldByLoc := byLoc(oldURLs)
+	newByLoc := byLoc(newURLs)
+
+	diff := Diff{
+		OldCount: len(oldByLoc),
+		NewCount: len(newByLoc),
+		Added:    make([]string, 0),
+		Removed:  make([]string, 0),
+		Changed:  make([]LastmodChange, 0),
+	}
+	dirs := make(map[string]*DirectoryCount)
+	dir := func(loc string) *DirectoryCount {
+		name := directory(loc)
+		if dirs[name] == nil {
+			dirs[name] = &DirectoryCount{Directory: name}
+		}
+		return dirs[name]
+	}
+
+	for loc, newMod := range newByLoc {
+		oldMod, ok := oldByLoc[loc]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, loc)
+			dir(loc).Added++
+		case !sameLastmod(oldMod, newMod):
+			diff.Changed = append(diff.Changed, LastmodChange{Loc: loc, Old: oldMod, New: newMod})
+			dir(loc).Changed++
+		}
+	}
+	for loc := range oldByLoc {
+		if _, ok := newByLoc[loc]; !ok {
+			diff.Removed = append(diff.Removed, loc)
+			dir(loc).Removed++
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Loc < diff.Changed[j].Loc })
+
+	diff.Directories = make([]DirectoryCount, 0, len(dirs))
+	for _, count := range dirs {
+		diff.Directories = append(diff.Directories, *count)
+	}
+	sort.Slice(diff.Directories, func(i, j int) bool {
+		return diff.Directories[i].Directory < diff.Directories[j].Directory
+	})
+	return diff
+}
+
+// byLoc maps each loc to its raw lastmod; a loc listed twice keeps the last
+// value.
+func byLoc(urls []URL) map[string]string {
+	m := make(map[string]string, len(urls))
+	for _, u := range urls {
+		if u.Loc != "" {
+			m[u.Loc] = u.LastMod
+		}
+	}
+	return m
+}
+
+func sameLastmod(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ta, okA := lastmod.ParseTime(a)
+	tb, okB := lastmod.ParseTime(b)
+	return okA && okB && ta.Equal(tb)
+}
+
+func directory(loc string) string {
+	path := loc
+	if parsed, err := url.Parse(loc); err == nil {
+		path = parsed.Path
+	}
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.Index(path, "/"); i >= 0 {
+		return "/" + path[:i+1]
+	}
+	return "/"
+}
@@ -0,0 +1,53 @@
+package sitemap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	oldURLs := []URL{
+		{Loc: "https://example.com/", LastMod: "2025-01-01"},
+		{Loc: "https://example.com/blog/a", LastMod: "2025-01-01"},
+		{Loc: "https://example.com/blog/b", LastMod: "2025-01-01"},
+		{Loc: "https://example.com/about", LastMod: "2025-01-02"},
+	}
+	newURLs := []URL{
+		{Loc: "https://example.com/", LastMod: "2025-01-01T00:00:00Z"},
+		{Loc: "https://example.com/blog/a", LastMod: "2025-02-01"},
+		{Loc: "https://example.com/blog/c", LastMod: "2025-02-01"},
+		{Loc: "https://example.com/about", LastMod: "2025-01-02"},
+		{Loc: "https://example.com/docs/x/y"},
+	}
+
+	diff := Compare(oldURLs, newURLs)
+
+	if diff.OldCount != 4 || diff.NewCount != 5 {
+		t.Errorf("counts=%d/%d, want 4/5", diff.OldCount, diff.NewCount)
+	}
+	if want := []string{"https://example.com/blog/c", "https://example.com/docs/x/y"}; !reflect.DeepEqual(diff.Added, want) {
+		t.Errorf("Added=%v, want %v", diff.Added, want)
+	}
+	if want := []string{"https://example.com/blog/b"}; !reflect.DeepEqual(diff.Removed, want) {
+		t.Errorf("Removed=%v, want %v", diff.Removed, want)
+	}
+	wantChanged := []LastmodChange{{Loc: "https://example.com/blog/a", Old: "2025-01-01", New: "2025-02-01"}}
+	if !reflect.DeepEqual(diff.Changed, wantChanged) {
+		t.Errorf("Changed=%+v, want %+v", diff.Changed, wantChanged)
+	}
+	wantDirs := []DirectoryCount{
+		{Directory: "/blog/", Added: 1, Removed: 1, Changed: 1},
+		{Directory: "/docs/", Added: 1},
+	}
+	if !reflect.DeepEqual(diff.Directories, wantDirs) {
+		t.Errorf("Directories=%+v, want %+v", diff.Directories, wantDirs)
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	urls := []URL{{Loc: "https://example.com/", LastMod: "2025-01-01"}}
+	diff := Compare(urls, urls)
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed)+len(diff.Directories) != 0 {
+		t.Errorf("expected empty diff, got %+v", diff)
+	}
+}
@@ -0,0 +1,144 @@
+package sitemap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tariktz/gopherseo/internal/lastmod"
+	"github.com/tariktz/gopherseo/internal/urlglob"
+)
+
+// ChangefreqAuto derives <changefreq> from how often a page changed across
+// crawls according to the lastmod store, or from the age of its lastmod
+// when there is no history.
+const ChangefreqAuto = "auto"
+
+// changefreqs lists the <changefreq> values defined by the sitemap protocol.
+var changefreqs = map[string]struct{}{
+	"always": {}, "hourly": {}, "daily": {}, "weekly": {}, "monthly": {}, "yearly": {}, "never": {},
+}
+
+// Rule assigns <changefreq> and/or <priority> to the URLs it matches. All
+// set conditions must hold for a rule to match.
+type Rule struct {
+	// Pattern is a URL glob with the same syntax as --exclude. Empty matches
+	// every URL.
+	Pattern string `json:"pattern,omitempty"`
+	// MinDepth and MaxDepth bound the crawl depth (root = 0) of matched URLs.
+	MinDepth *int `json:"min_depth,omitempty"`
+	MaxDepth *int `json:"max_depth,omitempty"`
+	// Changefreq is a sitemap changefreq value or "auto".
+	Changefreq string `json:"changefreq,omitempty"`
+	// Priority is between 0.0 and 1.0.
+	Priority *float64 `json:"priority,omitempty"`
+}
+
+// Validate reports configuration errors in the rule.
+func (r Rule) Validate() error {
+	if r.Changefreq == "" && r.Priority == nil {
+		return fmt.Errorf("rule %q sets neither changefreq nor priority", r.Pattern)
+	}
+	if r.Changefreq != "" && r.Changefreq != ChangefreqAuto {
+		if _, ok := changefreqs[r.Changefreq]; !ok {
+			return fmt.Errorf("rule %q: invalid changefreq %q", r.Pattern, r.Changefreq)
+		}
+	}
+	if r.Priority != nil && (*r.Priority < 0 || *r.Priority > 1) {
+		return fmt.Errorf("rule %q: priority %.2f is outside 0.0-1.0", r.Pattern, *r.Priority)
+	}
+	if r.MinDepth != nil && r.MaxDepth != nil && *r.MinDepth > *r.MaxDepth {
+		return fmt.Errorf("rule %q: min_depth is greater than max_depth", r.Pattern)
+	}
+	return nil
+}
+
+func (r Rule) matches(link string, depth int, hasDepth bool) bool {
+	if r.Pattern != "" && !urlglob.Match(r.Pattern, link) {
+		return false
+	}
+	if r.MinDepth != nil && (!hasDepth || depth < *r.MinDepth) {
+		return false
+	}
+	if r.MaxDepth != nil && (!hasDepth || depth > *r.MaxDepth) {
+		return false
+	}
+	return true
+}
+
+// Assign evaluates rules in order for every URL. The first matching rule
+// that sets an attribute decides it, so a depth-based priority rule and a
+// glob-based changefreq rule can both apply to one URL. "auto" changefreq is
+// skipped for pages with neither a change history nor a lastmod other than
+// the crawl-time fallback.
+func Assign(rules []Rule, urls []string, depthByURL map[string]int, lastModified map[string]lastmod.Info, now time.Time) (map[string]string, map[string]float64) {
+	changefreq := make(map[string]string)
+	priority := make(map[string]float64)
+
+	for _, link := range urls {
+		depth, hasDepth := depthByURL[link]
+		freqSet, prioritySet := false, false
+
+		for _, rule := range rules {
+			if freqSet && prioritySet {
+				break
+			}
+			if !rule.matches(link, depth, hasDepth) {
+				continue
+			}
+
+			if !freqSet && rule.Changefreq != "" {
+				if rule.Changefreq != ChangefreqAuto {
+					changefreq[link] = rule.Changefreq
+					freqSet = true
+				} else if freq := autoChangefreq(lastModified[link], now); freq != "" {
+					changefreq[link] = freq
+					freqSet = true
+				}
+			}
+			if !prioritySet && rule.Priority != nil {
+				priority[link] = *rule.Priority
+				prioritySet = true
+			}
+		}
+	}
+
+	return changefreq, priority
+}
+
+// autoChangefreq returns the "auto" changefreq for info, or "" when nothing
+// is known about how often the page changes. A page that has not changed
+// for longer than its usual interval is rated by that age instead.
+func autoChangefreq(info lastmod.Info, now time.Time) string {
+	if info.ChangeInterval > 0 {
+		interval := info.ChangeInterval
+		if age := now.Sub(info.Time); age > interval {
+			interval = age
+		}
+		return ChangefreqFromInterval(interval)
+	}
+	if info.Time.IsZero() || info.Source == lastmod.SourceFallback {
+		return ""
+	}
+	return ChangefreqFromAge(info.Time, now)
+}
+
+// ChangefreqFromAge maps the time since a page last changed to a changefreq
+// value.
+func ChangefreqFromAge(lastModified, now time.Time) string {
+	return ChangefreqFromInterval(now.Sub(lastModified))
+}
+
+// ChangefreqFromInterval maps the typical time between changes to a
+// changefreq value.
+func ChangefreqFromInterval(interval time.Duration) string {
+	switch {
+	case interval <= 24*time.Hour:
+		return "daily"
+	case interval <= 7*24*time.Hour:
+		return "weekly"
+	case interval <= 31*24*time.Hour:
+		return "monthly"
+	default:
+		return "yearly"
+	}
+}
@@ -0,0 +1,109 @@
+package sitemap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tariktz/gopherseo/internal/lastmod"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestAssign(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	rules := []Rule{
+		{MaxDepth: intPtr(0), Changefreq: "daily", Priority: floatPtr(1.0)},
+		{Pattern: "/blog/*", Changefreq: ChangefreqAuto},
+		{MinDepth: intPtr(1), MaxDepth: intPtr(1), Priority: floatPtr(0.8)},
+		{Priority: floatPtr(0.3)},
+	}
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/blog/fresh",
+		"https://example.com/blog/new-unknown",
+		"https://example.com/blog/history",
+		"https://example.com/blog/stale-history",
+		"https://example.com/docs/deep",
+	}
+	depth := map[string]int{
+		"https://example.com/":                   0,
+		"https://example.com/blog/fresh":         1,
+		"https://example.com/blog/new-unknown":   1,
+		"https://example.com/blog/history":       1,
+		"https://example.com/blog/stale-history": 1,
+		"https://example.com/docs/deep":          3,
+	}
+	infos := map[string]lastmod.Info{
+		"https://example.com/blog/fresh":       {Time: now.Add(-3 * 24 * time.Hour), Source: lastmod.SourceJSONLD},
+		"https://example.com/blog/new-unknown": {Time: now, Source: lastmod.SourceFallback},
+		// Changed in this crawl, but usually only every 20 days.
+		"https://example.com/blog/history": {Time: now, Source: lastmod.SourceFallback, ChangeInterval: 20 * 24 * time.Hour},
+		// Used to change daily, unchanged for 100 days.
+		"https://example.com/blog/stale-history": {Time: now.Add(-100 * 24 * time.Hour), Source: lastmod.SourceHistory, ChangeInterval: 24 * time.Hour},
+	}
+
+	changefreq, priority := Assign(rules, urls, depth, infos, now)
+
+	wantFreq := map[string]string{
+		"https://example.com/":                   "daily",
+		"https://example.com/blog/fresh":         "weekly",
+		"https://example.com/blog/history":       "monthly",
+		"https://example.com/blog/stale-history": "yearly",
+	}
+	if len(changefreq) != len(wantFreq) {
+		t.Errorf("changefreq=%v, want %v", changefreq, wantFreq)
+	}
+	for u, want := range wantFreq {
+		if changefreq[u] != want {
+			t.Errorf("changefreq[%s]=%q, want %q", u, changefreq[u], want)
+		}
+	}
+
+	wantPriority := map[string]float64{
+		"https://example.com/":                 1.0,
+		"https://example.com/blog/fresh":       0.8,
+		"https://example.com/blog/new-unknown": 0.8,
+		"https://example.com/docs/deep":        0.3,
+	}
+	for u, want := range wantPriority {
+		if got, ok := priority[u]; !ok || got != want {
+			t.Errorf("priority[%s]=%v, want %v", u, got, want)
+		}
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		ok   bool
+	}{
+		{"valid", Rule{Pattern: "*", Changefreq: "weekly", Priority: floatPtr(0.5)}, true},
+		{"auto", Rule{Changefreq: ChangefreqAuto}, true},
+		{"empty", Rule{Pattern: "*"}, false},
+		{"bad changefreq", Rule{Changefreq: "sometimes"}, false},
+		{"bad priority", Rule{Priority: floatPtr(1.5)}, false},
+		{"bad depth range", Rule{MinDepth: intPtr(3), MaxDepth: intPtr(1), Priority: floatPtr(0.1)}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestChangefreqFromAge(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	tests := map[time.Duration]string{
+		2 * time.Hour:        "daily",
+		5 * 24 * time.Hour:   "weekly",
+		20 * 24 * time.Hour:  "monthly",
+		200 * 24 * time.Hour: "yearly",
+	}
+	for age, want := range tests {
+		if got := ChangefreqFromAge(now.Add(-age), now); got != want {
+			t.Errorf("ChangefreqFromAge(%v) = %q, want %q", age, got, want)
+		}
+	}
+}
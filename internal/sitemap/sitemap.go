@@ -0,0 +1,178 @@
+// Package sitemap defines the sitemap XML model shared by the writer, reader
+// and validator, reads existing XML sitemaps and sitemap indexes from local
+// files or URLs, transparently handling gzip compression, and assigns
+// <changefreq> and <priority> values from configured rules.
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tariktz/gopherseo/internal/lastmod"
+)
+
+// maxIndexDepth bounds how many nested sitemap indexes Load follows.
+const maxIndexDepth = 3
+
+// Document is a parsed sitemap file: either a <urlset> with URLs or a
+// <sitemapindex> with child sitemap locations.
+type Document struct {
+	// Index is true for a <sitemapindex>.
+	Index    bool
+	URLs     []URL
+	Sitemaps []string
+}
+
+// xmlDocument decodes either root element.
+type xmlDocument struct {
+	XMLName  xml.Name
+	URLs     []URL        `xml:"url"`
+	Sitemaps []IndexEntry `xml:"sitemap"`
+}
+
+// Parse decodes a sitemap or sitemap index. Gzip-compressed input is
+// detected and decompressed.
+func Parse(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read sitemap: %w", err)
+	}
+	data, err = decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw xmlDocument
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode sitemap: %w", err)
+	}
+
+	doc := &Document{}
+	switch raw.XMLName.Local {
+	case "urlset":
+		for _, entry := range raw.URLs {
+			doc.URLs = append(doc.URLs, URL{
+				Loc:        strings.TrimSpace(entry.Loc),
+				LastMod:    strings.TrimSpace(entry.LastMod),
+				ChangeFreq: strings.TrimSpace(entry.ChangeFreq),
+				Priority:   strings.TrimSpace(entry.Priority),
+			})
+		}
+	case "sitemapindex":
+		doc.Index = true
+		for _, entry := range raw.Sitemaps {
+			doc.Sitemaps = append(doc.Sitemaps, strings.TrimSpace(entry.Loc))
+		}
+	default:
+		return nil, fmt.Errorf("decode sitemap: unexpected root element <%s>", raw.XMLName.Local)
+	}
+	return doc, nil
+}
+
+// Load reads the sitemap at location, a file path or an http(s) URL, and
+// returns every URL it lists. Sitemap indexes are followed.
+func Load(client *http.Client, location string) ([]URL, error) {
+	return load(client, location, 0)
+}
+
+func load(client *http.Client, location string, depth int) ([]URL, error) {
+	doc, err := open(client, location)
+	if err != nil {
+		return nil, err
+	}
+	if !doc.Index {
+		return doc.URLs, nil
+	}
+	if depth >= maxIndexDepth {
+		return nil, fmt.Errorf("sitemap index %s nests deeper than %d levels", location, maxIndexDepth)
+	}
+
+	urls := make([]URL, 0)
+	for _, child := range doc.Sitemaps {
+		childURLs, err := load(client, child, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, childURLs...)
+	}
+	return urls, nil
+}
+
+func open(client *http.Client, location string) (*Document, error) {
+	data, err := fetch(client, location)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(data))
+}
+
+// fetch returns the raw, possibly compressed, bytes of the sitemap at
+// location.
+func fetch(client *http.Client, location string) ([]byte, error) {
+	if !isRemote(location) {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("open sitemap: %w", err)
+		}
+		return data, nil
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("fetch sitemap %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch sitemap %s: unexpected status %d", location, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read sitemap %s: %w", location, err)
+	}
+	return body, nil
+}
+
+// decompress gunzips data when it starts with the gzip magic bytes and
+// returns it unchanged otherwise.
+func decompress(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("open gzip sitemap: %w", err)
+	}
+	defer gz.Close()
+	out, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("decompress sitemap: %w", err)
+	}
+	return out, nil
+}
+
+// LastModified maps each URL with a parsable <lastmod> to its time in UTC.
+func LastModified(urls []URL) map[string]time.Time {
+	times := make(map[string]time.Time, len(urls))
+	for _, u := range urls {
+		if t, ok := lastmod.ParseTime(u.LastMod); ok {
+			times[u.Loc] = t.UTC()
+		}
+	}
+	return times
+}
+
+func isRemote(location string) bool {
+	lower := strings.ToLower(location)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
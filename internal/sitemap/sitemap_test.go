@@ -0,0 +1,101 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const urlsetXML = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc><lastmod>2025-01-02</lastmod></url>
+  <url><loc> https://example.com/about </loc></url>
+</urlset>`
+
+func TestParse_URLSet(t *testing.T) {
+	doc, err := Parse(strings.NewReader(urlsetXML))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.Index || len(doc.URLs) != 2 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	if doc.URLs[1].Loc != "https://example.com/about" {
+		t.Errorf("Loc=%q, want trimmed", doc.URLs[1].Loc)
+	}
+}
+
+func TestParse_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte(urlsetXML))
+	_ = gz.Close()
+
+	doc, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.URLs) != 2 {
+		t.Fatalf("URLs=%d, want 2", len(doc.URLs))
+	}
+}
+
+func TestParse_UnexpectedRoot(t *testing.T) {
+	if _, err := Parse(strings.NewReader(`<html></html>`)); err == nil {
+		t.Fatal("expected error for non-sitemap document")
+	}
+}
+
+func TestLoad_IndexOverHTTP(t *testing.T) {
+	mux := http.NewServeMux()
+	var base string
+	mux.HandleFunc("/sitemap_index.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+			<sitemap><loc>%s/pages.xml</loc></sitemap>
+		</sitemapindex>`, base)
+	})
+	mux.HandleFunc("/pages.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, urlsetXML)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	base = ts.URL
+
+	urls, err := Load(ts.Client(), ts.URL+"/sitemap_index.xml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(urls) != 2 {
+		t.Fatalf("URLs=%d, want 2", len(urls))
+	}
+}
+
+func TestLoad_FileAndLastModified(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sitemap.xml")
+	if err := os.WriteFile(path, []byte(urlsetXML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	urls, err := Load(nil, path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	times := LastModified(urls)
+	if len(times) != 1 || !times["https://example.com/"].Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("LastModified=%v", times)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	if _, err := Load(nil, filepath.Join(t.TempDir(), "missing.xml")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
@@ -0,0 +1,429 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tariktz/gopherseo/internal/canonical"
+	"github.com/tariktz/gopherseo/internal/robots"
+)
+
+// Protocol limits from sitemaps.org.
+const (
+	// MaxURLs is the maximum number of <url> or <sitemap> entries per file.
+	MaxURLs = 50000
+	// MaxBytes is the maximum uncompressed size of a sitemap file.
+	MaxBytes = 50 * 1024 * 1024
+	// MaxLocLength is the maximum length of a <loc> value.
+	MaxLocLength = 2048
+)
+
+// w3cDatetime matches the W3C Datetime profile of ISO 8601 used by
+// <lastmod>: YYYY, YYYY-MM, YYYY-MM-DD, or a date with hh:mm[:ss[.s]] and a
+// mandatory time zone designator.
+var w3cDatetime = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2}(T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2}))?)?)?$`)
+
+// IssueType describes a sitemap validation problem category.
+type IssueType string
+
+const (
+	IssueInvalidXML          IssueType = "invalid_xml"
+	IssueInvalidRoot         IssueType = "invalid_root"
+	IssueWrongNamespace      IssueType = "wrong_namespace"
+	IssueUndeclaredNamespace IssueType = "undeclared_namespace"
+	IssueUnknownElement      IssueType = "unknown_element"
+	IssueTooLarge            IssueType = "too_large"
+	IssueTooManyURLs         IssueType = "too_many_urls"
+	IssueMissingLoc          IssueType = "missing_loc"
+	IssueInvalidLoc          IssueType = "invalid_loc"
+	IssueLocTooLong          IssueType = "loc_too_long"
+	IssueInvalidLastmod      IssueType = "invalid_lastmod"
+	IssueInvalidChangefreq   IssueType = "invalid_changefreq"
+	IssueInvalidPriority     IssueType = "invalid_priority"
+	IssueDuplicateLoc        IssueType = "duplicate_loc"
+	IssueCrossHostLoc        IssueType = "cross_host_loc"
+	IssueChildUnreachable    IssueType = "child_unreachable"
+	// Issues found when fetching each <loc> (ValidateOptions.FetchLocs).
+	IssueLocStatus       IssueType = "loc_status"
+	IssueLocRedirect     IssueType = "loc_redirect"
+	IssueLocNoindex      IssueType = "loc_noindex"
+	IssueLocNonCanonical IssueType = "loc_non_canonical"
+	IssueLocUnreachable  IssueType = "loc_unreachable"
+)
+
+// Issue is a validation finding. Loc is empty for file-level issues.
+type Issue struct {
+	Sitemap string
+	Loc     string
+	Type    IssueType
+	Detail  string
+}
+
+// ValidateOptions configures Validate.
+type ValidateOptions struct {
+	// FetchLocs requests every <loc> and reports non-200, redirecting,
+	// noindex and non-canonical entries.
+	FetchLocs bool
+	// UserAgent is sent when fetching locs.
+	UserAgent string
+	// Threads bounds concurrent loc requests (default 5).
+	Threads int
+}
+
+// Report is the result of validating a sitemap and, for indexes, every child
+// sitemap it lists.
+type Report struct {
+	// Sitemaps lists every validated sitemap location.
+	Sitemaps []string
+	// URLCount is the total number of <url> entries across all sitemaps.
+	URLCount int
+	Issues   []Issue
+}
+
+// entry is a <url> or <sitemap> element as written in the document. URL.Loc
+// holds the first <loc>; locCount counts every <loc>.
+type entry struct {
+	URL
+	locCount int
+}
+
+// Validate checks the sitemap or sitemap index at location (a file path or
+// http(s) URL). An error is returned only when location itself cannot be
+// read; problems in the document or its children are reported as issues.
+func Validate(client *http.Client, location string, opts ValidateOptions) (Report, error) {
+	report := Report{Issues: make([]Issue, 0)}
+
+	data, err := fetch(client, location)
+	if err != nil {
+		return report, err
+	}
+
+	locs := make([]Issue, 0)
+	seen := make(map[string]struct{})
+	validateFile(client, location, data, 0, &report, &locs, seen)
+
+	if opts.FetchLocs {
+		report.Issues = append(report.Issues, fetchLocs(client, locs, opts)...)
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Sitemap != report.Issues[j].Sitemap {
+			return report.Issues[i].Sitemap < report.Issues[j].Sitemap
+		}
+		return report.Issues[i].Loc < report.Issues[j].Loc
+	})
+	return report, nil
+}
+
+// validateFile validates one document and recurses into index children.
+// Page locs are appended to locs (as issue stubs carrying Sitemap and Loc);
+// seen tracks locs across all files for duplicate detection.
+func validateFile(client *http.Client, location string, raw []byte, depth int, report *Report, locs *[]Issue, seen map[string]struct{}) {
+	report.Sitemaps = append(report.Sitemaps, location)
+	add := func(loc string, issueType IssueType, detail string) {
+		report.Issues = append(report.Issues, Issue{Sitemap: location, Loc: loc, Type: issueType, Detail: detail})
+	}
+
+	data, err := decompress(raw)
+	if err != nil {
+		add("", IssueInvalidXML, err.Error())
+		return
+	}
+	if len(data) > MaxBytes {
+		add("", IssueTooLarge, fmt.Sprintf("uncompressed size %d bytes exceeds %d", len(data), MaxBytes))
+	}
+
+	root, entries, problems := scan(data)
+	for _, p := range problems {
+		add("", p.Type, p.Detail)
+	}
+	if root == "" {
+		return
+	}
+
+	if len(entries) > MaxURLs {
+		add("", IssueTooManyURLs, fmt.Sprintf("%d entries exceed the limit of %d", len(entries), MaxURLs))
+	}
+
+	baseHost := ""
+	if isRemote(location) {
+		if parsed, err := url.Parse(location); err == nil {
+			baseHost = strings.ToLower(parsed.Hostname())
+		}
+	}
+
+	children := make([]string, 0)
+	for _, e := range entries {
+		if e.locCount == 0 {
+			add("", IssueMissingLoc, "entry has no <loc>")
+			continue
+		}
+		loc := e.Loc
+		if e.locCount > 1 {
+			add(loc, IssueInvalidLoc, fmt.Sprintf("entry has %d <loc> elements", e.locCount))
+		}
+
+		parsed, err := url.Parse(loc)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			add(loc, IssueInvalidLoc, "loc is not an absolute http(s) URL")
+			continue
+		}
+		if len(loc) > MaxLocLength {
+			add(loc, IssueLocTooLong, fmt.Sprintf("loc is %d characters long (max %d)", len(loc), MaxLocLength))
+		}
+
+		host := strings.ToLower(parsed.Hostname())
+		if baseHost == "" {
+			baseHost = host
+		} else if host != baseHost {
+			add(loc, IssueCrossHostLoc, fmt.Sprintf("loc host %s differs from %s", host, baseHost))
+		}
+
+		if e.LastMod != "" && !w3cDatetime.MatchString(e.LastMod) {
+			add(loc, IssueInvalidLastmod, fmt.Sprintf("lastmod %q is not a W3C datetime", e.LastMod))
+		}
+
+		if root == "sitemapindex" {
+			children = append(children, loc)
+			continue
+		}
+
+		report.URLCount++
+		if e.ChangeFreq != "" {
+			if _, ok := changefreqs[e.ChangeFreq]; !ok {
+				add(loc, IssueInvalidChangefreq, fmt.Sprintf("changefreq %q is not a protocol value", e.ChangeFreq))
+			}
+		}
+		if e.Priority != "" {
+			if p, err := strconv.ParseFloat(e.Priority, 64); err != nil || p < 0 || p > 1 {
+				add(loc, IssueInvalidPriority, fmt.Sprintf("priority %q is not between 0.0 and 1.0", e.Priority))
+			}
+		}
+		if _, dup := seen[loc]; dup {
+			add(loc, IssueDuplicateLoc, "loc is listed more than once")
+			continue
+		}
+		seen[loc] = struct{}{}
+		*locs = append(*locs, Issue{Sitemap: location, Loc: loc})
+	}
+
+	for _, child := range children {
+		if depth+1 > maxIndexDepth {
+			add(child, IssueChildUnreachable, fmt.Sprintf("sitemap indexes nest deeper than %d levels", maxIndexDepth))
+			continue
+		}
+		childData, err := fetch(client, child)
+		if err != nil {
+			add(child, IssueChildUnreachable, err.Error())
+			continue
+		}
+		validateFile(client, child, childData, depth+1, report, locs, seen)
+	}
+}
+
+// scan walks the XML tokens of a sitemap document, checking structure and
+// namespaces, and returns the root element name ("urlset" or
+// "sitemapindex", empty when unusable) with its entries.
+func scan(data []byte) (string, []entry, []Issue) {
+	problems := make([]Issue, 0)
+	entries := make([]entry, 0)
+	root := ""
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	rootSpace, entryName, field := "", "", ""
+	var text strings.Builder
+	var current *entry
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			problems = append(problems, Issue{Type: IssueInvalidXML, Detail: err.Error()})
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			space := t.Name.Space
+			if space != "" && !strings.Contains(space, ":") {
+				// encoding/xml leaves an undeclared prefix as the namespace.
+				problems = append(problems, Issue{Type: IssueUndeclaredNamespace,
+					Detail: fmt.Sprintf("prefix %q on <%s:%s> is not declared", space, space, t.Name.Local)})
+			}
+
+			switch depth {
+			case 0:
+				switch t.Name.Local {
+				case "urlset", "sitemapindex":
+					root = t.Name.Local
+				default:
+					problems = append(problems, Issue{Type: IssueInvalidRoot,
+						Detail: fmt.Sprintf("root element is <%s>, want <urlset> or <sitemapindex>", t.Name.Local)})
+					return "", entries, problems
+				}
+				rootSpace = space
+				entryName = "url"
+				if root == "sitemapindex" {
+					entryName = "sitemap"
+				}
+				if space != Namespace {
+					problems = append(problems, Issue{Type: IssueWrongNamespace,
+						Detail: fmt.Sprintf("root namespace is %q, want %q", space, Namespace)})
+				}
+			case 1:
+				if t.Name.Local != entryName || space != rootSpace {
+					problems = append(problems, Issue{Type: IssueUnknownElement,
+						Detail: fmt.Sprintf("unexpected <%s> in <%s>", t.Name.Local, root)})
+				} else {
+					entries = append(entries, entry{})
+					current = &entries[len(entries)-1]
+				}
+			case 2:
+				field = ""
+				if current != nil && space == rootSpace {
+					switch t.Name.Local {
+					case "loc", "lastmod", "changefreq", "priority":
+						field = t.Name.Local
+						text.Reset()
+					default:
+						problems = append(problems, Issue{Type: IssueUnknownElement,
+							Detail: fmt.Sprintf("unexpected <%s> in <%s>", t.Name.Local, entryName)})
+					}
+				}
+			}
+			depth++
+
+		case xml.CharData:
+			if depth == 3 && field != "" {
+				text.Write(t)
+			}
+
+		case xml.EndElement:
+			depth--
+			switch depth {
+			case 2:
+				if current != nil && field != "" {
+					value := strings.TrimSpace(text.String())
+					switch field {
+					case "loc":
+						if current.locCount == 0 {
+							current.Loc = value
+						}
+						current.locCount++
+					case "lastmod":
+						current.LastMod = value
+					case "changefreq":
+						current.ChangeFreq = value
+					case "priority":
+						current.Priority = value
+					}
+				}
+				field = ""
+			case 1:
+				if current != nil && current.locCount == 1 && current.Loc == "" {
+					current.locCount = 0
+				}
+				current = nil
+			}
+		}
+	}
+
+	return root, entries, problems
+}
+
+// fetchLocs requests every loc without following redirects and reports
+// non-200, redirecting, noindex and non-canonical entries.
+func fetchLocs(client *http.Client, locs []Issue, opts ValidateOptions) []Issue {
+	threads := opts.Threads
+	if threads <= 0 {
+		threads = 5
+	}
+
+	noRedirect := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	if client != nil {
+		noRedirect.Transport = client.Transport
+		noRedirect.Timeout = client.Timeout
+		noRedirect.Jar = client.Jar
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		issues = make([]Issue, 0)
+	)
+	sem := make(chan struct{}, threads)
+	for _, loc := range locs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(loc Issue) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			found := checkLoc(noRedirect, loc.Loc, opts.UserAgent)
+			for i := range found {
+				found[i].Sitemap = loc.Sitemap
+			}
+			mu.Lock()
+			issues = append(issues, found...)
+			mu.Unlock()
+		}(loc)
+	}
+	wg.Wait()
+	return issues
+}
+
+func checkLoc(client *http.Client, loc, userAgent string) []Issue {
+	issue := func(issueType IssueType, detail string) []Issue {
+		return []Issue{{Loc: loc, Type: issueType, Detail: detail}}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, loc, nil)
+	if err != nil {
+		return issue(IssueLocUnreachable, err.Error())
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return issue(IssueLocUnreachable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return issue(IssueLocRedirect, fmt.Sprintf("responds with %d to %s", resp.StatusCode, resp.Header.Get("Location")))
+	case resp.StatusCode != http.StatusOK:
+		return issue(IssueLocStatus, fmt.Sprintf("responds with %d", resp.StatusCode))
+	}
+
+	doc, _ := goquery.NewDocumentFromReader(resp.Body)
+	found := make([]Issue, 0)
+	if robots.IsNoindex(resp.Header, doc) {
+		found = append(found, Issue{Loc: loc, Type: IssueLocNoindex, Detail: "page is marked noindex"})
+	}
+
+	pageURL, ok := canonical.Normalize(loc)
+	if !ok {
+		return found
+	}
+	info := canonical.MergeHeader(canonical.Extract(pageURL, doc), canonical.HeaderHref(resp.Header))
+	if info.CanonicalURL != "" && info.CanonicalURL != pageURL {
+		found = append(found, Issue{Loc: loc, Type: IssueLocNonCanonical, Detail: "canonical points to " + info.CanonicalURL})
+	}
+	return found
+}
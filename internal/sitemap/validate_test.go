@@ -0,0 +1,200 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSitemap(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sitemap.xml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write sitemap: %v", err)
+	}
+	return path
+}
+
+func issueTypes(report Report) map[IssueType]int {
+	types := make(map[IssueType]int)
+	for _, issue := range report.Issues {
+		types[issue.Type]++
+	}
+	return types
+}
+
+func TestValidate_ValidURLSet(t *testing.T) {
+	path := writeSitemap(t, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+  <url>
+    <loc>https://example.com/</loc>
+    <lastmod>2025-01-02T10:00:00+01:00</lastmod>
+    <changefreq>daily</changefreq>
+    <priority>0.8</priority>
+    <image:image><image:loc>https://example.com/a.png</image:loc></image:image>
+  </url>
+  <url><loc>https://example.com/about</loc><lastmod>2025-01</lastmod></url>
+</urlset>`)
+
+	report, err := Validate(http.DefaultClient, path, ValidateOptions{})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", report.Issues)
+	}
+	if report.URLCount != 2 || len(report.Sitemaps) != 1 {
+		t.Errorf("URLCount=%d Sitemaps=%v", report.URLCount, report.Sitemaps)
+	}
+}
+
+func TestValidate_EntryProblems(t *testing.T) {
+	path := writeSitemap(t, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/a</loc><lastmod>02/01/2025</lastmod></url>
+  <url><loc>https://example.com/a</loc></url>
+  <url><loc>https://other.example/b</loc></url>
+  <url><loc>/relative</loc></url>
+  <url><lastmod>2025-01-02</lastmod></url>
+  <url><loc>https://example.com/c</loc><changefreq>sometimes</changefreq><priority>1.5</priority></url>
+  <url><loc>https://example.com/d</loc><extra>x</extra></url>
+  <url><loc>https://example.com/e</loc><image:image/></url>
+</urlset>`)
+
+	report, err := Validate(http.DefaultClient, path, ValidateOptions{})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	types := issueTypes(report)
+	for _, want := range []IssueType{
+		IssueInvalidLastmod, IssueDuplicateLoc, IssueCrossHostLoc, IssueInvalidLoc,
+		IssueMissingLoc, IssueInvalidChangefreq, IssueInvalidPriority,
+		IssueUnknownElement, IssueUndeclaredNamespace,
+	} {
+		if types[want] != 1 {
+			t.Errorf("%s count=%d, want 1 (issues: %+v)", want, types[want], report.Issues)
+		}
+	}
+}
+
+func TestValidate_RootAndNamespace(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want IssueType
+	}{
+		{"wrong root", `<rss><channel/></rss>`, IssueInvalidRoot},
+		{"wrong namespace", `<urlset xmlns="http://www.google.com/schemas/sitemap/0.84"><url><loc>https://example.com/</loc></url></urlset>`, IssueWrongNamespace},
+		{"missing namespace", `<urlset><url><loc>https://example.com/</loc></url></urlset>`, IssueWrongNamespace},
+		{"broken xml", `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url>`, IssueInvalidXML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Validate(http.DefaultClient, writeSitemap(t, tt.body), ValidateOptions{})
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if issueTypes(report)[tt.want] == 0 {
+				t.Errorf("expected %s, got %+v", tt.want, report.Issues)
+			}
+		})
+	}
+}
+
+func TestValidate_IndexFollowsGzipChildren(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/sitemap_index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>%[1]s/a.xml.gz</loc></sitemap>
+<sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+</sitemapindex>`, srv.URL)
+	})
+	mux.HandleFunc("/a.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		fmt.Fprintf(gz, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>%s/page</loc></url></urlset>`, srv.URL)
+		_ = gz.Close()
+		_, _ = w.Write(buf.Bytes())
+	})
+
+	report, err := Validate(srv.Client(), srv.URL+"/sitemap_index.xml", ValidateOptions{})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if report.URLCount != 1 || len(report.Sitemaps) != 2 {
+		t.Errorf("URLCount=%d Sitemaps=%v", report.URLCount, report.Sitemaps)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Type != IssueChildUnreachable ||
+		!strings.HasSuffix(report.Issues[0].Loc, "/missing.xml") {
+		t.Errorf("expected one child_unreachable issue, got %+v", report.Issues)
+	}
+}
+
+func TestValidate_FetchLocs(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		for _, p := range []string{"ok", "moved", "gone", "noindex", "alias"} {
+			fmt.Fprintf(w, `<url><loc>%s/%s</loc></url>`, srv.URL, p)
+		}
+		fmt.Fprint(w, `</urlset>`)
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><head><link rel="canonical" href="%s/ok"></head></html>`, srv.URL)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+	mux.HandleFunc("/noindex", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><meta name="robots" content="noindex"></head></html>`)
+	})
+	mux.HandleFunc("/alias", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/ok>; rel="canonical"`, srv.URL))
+	})
+
+	report, err := Validate(srv.Client(), srv.URL+"/sitemap.xml", ValidateOptions{FetchLocs: true, Threads: 2})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	got := make(map[string]IssueType)
+	for _, issue := range report.Issues {
+		got[strings.TrimPrefix(issue.Loc, srv.URL)] = issue.Type
+	}
+	want := map[string]IssueType{
+		"/moved":   IssueLocRedirect,
+		"/gone":    IssueLocStatus,
+		"/noindex": IssueLocNoindex,
+		"/alias":   IssueLocNonCanonical,
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), report.Issues)
+	}
+	for loc, issueType := range want {
+		if got[loc] != issueType {
+			t.Errorf("%s: got %q, want %q", loc, got[loc], issueType)
+		}
+	}
+}
+
+func TestValidate_UnreadableLocation(t *testing.T) {
+	if _, err := Validate(http.DefaultClient, filepath.Join(t.TempDir(), "missing.xml"), ValidateOptions{}); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
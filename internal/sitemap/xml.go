@@ -0,0 +1,84 @@
+package sitemap
+
+import "encoding/xml"
+
+// Sitemap XML namespaces.
+const (
+	// Namespace is the sitemap protocol XML namespace.
+	Namespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	ImageNamespace = "http://www.google.com/schemas/sitemap-image/1.1"
+	VideoNamespace = "http://www.google.com/schemas/sitemap-video/1.1"
+	NewsNamespace  = "http://www.google.com/schemas/sitemap-news/0.9"
+	XHTMLNamespace = "http://www.w3.org/1999/xhtml"
+)
+
+// URLSet is the root element of a Sitemap 0.9 XML document. Extension
+// namespaces are only declared when set.
+type URLSet struct {
+	XMLName    xml.Name `xml:"urlset"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	XmlnsImage string   `xml:"xmlns:image,attr,omitempty"`
+	XmlnsVideo string   `xml:"xmlns:video,attr,omitempty"`
+	XmlnsNews  string   `xml:"xmlns:news,attr,omitempty"`
+	XmlnsXHTML string   `xml:"xmlns:xhtml,attr,omitempty"`
+	URLs       []URL    `xml:"url"`
+}
+
+// URL is a single <url> entry of a sitemap. Values are kept as written in
+// the document. The extension fields are only encoded; Parse fills Loc,
+// LastMod, ChangeFreq and Priority.
+type URL struct {
+	Loc string `xml:"loc"`
+	// LastMod is the raw <lastmod> value, if any.
+	LastMod    string  `xml:"lastmod,omitempty"`
+	ChangeFreq string  `xml:"changefreq,omitempty"`
+	Priority   string  `xml:"priority,omitempty"`
+	Links      []Link  `xml:"xhtml:link,omitempty"`
+	Images     []Image `xml:"image:image,omitempty"`
+	Videos     []Video `xml:"video:video,omitempty"`
+	News       *News   `xml:"news:news,omitempty"`
+}
+
+// IndexEntry is a single <sitemap> entry of a sitemap index.
+type IndexEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Link is an <xhtml:link rel="alternate"> hreflang entry.
+type Link struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+// Image is a single <image:image> entry of the Google image sitemap
+// extension.
+type Image struct {
+	Loc string `xml:"image:loc"`
+}
+
+// Video is a single <video:video> entry of the Google video sitemap
+// extension.
+type Video struct {
+	ThumbnailLoc    string `xml:"video:thumbnail_loc"`
+	Title           string `xml:"video:title"`
+	Description     string `xml:"video:description"`
+	ContentLoc      string `xml:"video:content_loc,omitempty"`
+	PlayerLoc       string `xml:"video:player_loc,omitempty"`
+	Duration        int    `xml:"video:duration,omitempty"`
+	PublicationDate string `xml:"video:publication_date,omitempty"`
+}
+
+// News is the <news:news> entry of the Google news sitemap extension.
+type News struct {
+	Publication     NewsPublication `xml:"news:publication"`
+	PublicationDate string          `xml:"news:publication_date"`
+	Title           string          `xml:"news:title"`
+}
+
+// NewsPublication is the <news:publication> element of a News entry.
+type NewsPublication struct {
+	Name     string `xml:"news:name"`
+	Language string `xml:"news:language"`
+}
@@ -0,0 +1,161 @@
+// Package soft404 detects "soft 404" pages: error pages that respond with a
+// 200 status code instead of a proper 404/410.
+//
+// Detection relies on a fingerprint of the site's own error page, captured by
+// probing a random path that cannot exist under the root URL. A page is a
+// soft 404 when its content matches the fingerprint, or when its <title> or
+// first <h1> contains a common "not found" phrase and its content still
+// resembles the fingerprint. Phrases alone never flag a page.
+package soft404
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// similarityThreshold is the minimum Jaccard similarity between the word sets
+// of a page and the probed error page for the page to be considered a match.
+const similarityThreshold = 0.9
+
+// phraseSimilarityThreshold is the lower similarity that suffices when the
+// page's title or h1 also contains a "not found" phrase, e.g. for error pages
+// that echo the requested path.
+const phraseSimilarityThreshold = 0.5
+
+// notFoundPatterns lists lower-case phrases that, when present in a page's
+// title or h1, indicate the page is an error page.
+var notFoundPatterns = []string{
+	"page not found",
+	"not found",
+	"page does not exist",
+	"page doesn't exist",
+	"page cannot be found",
+	"page can't be found",
+	"no longer exists",
+	"no longer available",
+}
+
+var status404Pattern = regexp.MustCompile(`\b404\b`)
+
+// Fingerprint captures the content of a site's error page as served for a
+// path that does not exist.
+type Fingerprint struct {
+	// URL is the probe URL the error page was served at.
+	URL string
+	// Title is the trimmed <title> of the error page.
+	Title string
+
+	words map[string]struct{}
+}
+
+// ProbeURL returns a URL under root whose path is random and therefore
+// extremely unlikely to exist.
+func ProbeURL(root *url.URL) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate soft-404 probe path: %w", err)
+	}
+
+	probe := *root
+	probe.Path = "/gopherseo-probe-" + hex.EncodeToString(buf)
+	probe.RawQuery = ""
+	probe.Fragment = ""
+	return probe.String(), nil
+}
+
+// NewFingerprint builds a fingerprint from the error page served at finalURL.
+// It returns nil when doc is nil.
+func NewFingerprint(finalURL string, doc *goquery.Document) *Fingerprint {
+	if doc == nil {
+		return nil
+	}
+	return &Fingerprint{
+		URL:   finalURL,
+		Title: strings.TrimSpace(doc.Find("title").First().Text()),
+		words: wordSet(doc),
+	}
+}
+
+// Detect reports whether the page at pageURL looks like a soft 404. The
+// returned reason explains which signal matched. fp may be nil when the site
+// answered the probe with a proper error status; no page is flagged then.
+func Detect(pageURL string, doc *goquery.Document, fp *Fingerprint) (string, bool) {
+	// A page that is the probe's own final URL must not be flagged.
+	if doc == nil || fp == nil || fp.URL == pageURL || len(fp.words) == 0 {
+		return "", false
+	}
+
+	score := similarity(fp.words, wordSet(doc))
+	if score >= similarityThreshold {
+		return "content matches the site's error page", true
+	}
+	if score < phraseSimilarityThreshold {
+		return "", false
+	}
+
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	if pattern, ok := matchNotFound(title); ok {
+		return fmt.Sprintf("title contains %q and content resembles the site's error page", pattern), true
+	}
+
+	h1 := strings.TrimSpace(doc.Find("h1").First().Text())
+	if pattern, ok := matchNotFound(h1); ok {
+		return fmt.Sprintf("h1 contains %q and content resembles the site's error page", pattern), true
+	}
+
+	return "", false
+}
+
+func matchNotFound(text string) (string, bool) {
+	lower := strings.ToLower(text)
+	if lower == "" {
+		return "", false
+	}
+	for _, pattern := range notFoundPatterns {
+		if strings.Contains(lower, pattern) {
+			return pattern, true
+		}
+	}
+	if status404Pattern.MatchString(lower) {
+		return "404", true
+	}
+	return "", false
+}
+
+// wordSet returns the set of lower-cased words in the document body,
+// ignoring script and style content.
+func wordSet(doc *goquery.Document) map[string]struct{} {
+	body := doc.Find("body").Clone()
+	body.Find("script, style, noscript").Remove()
+
+	words := make(map[string]struct{})
+	for _, word := range strings.Fields(strings.ToLower(body.Text())) {
+		words[word] = struct{}{}
+	}
+	return words
+}
+
+// similarity computes the Jaccard index of two word sets.
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for word := range a {
+		if _, ok := b[word]; ok {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,145 @@
+package soft404
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func docFromHTML(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("build document: %v", err)
+	}
+	return doc
+}
+
+func TestProbeURL_RandomPathUnderRoot(t *testing.T) {
+	root, _ := url.Parse("https://example.com/?q=1")
+
+	first, err := ProbeURL(root)
+	if err != nil {
+		t.Fatalf("ProbeURL: %v", err)
+	}
+	second, err := ProbeURL(root)
+	if err != nil {
+		t.Fatalf("ProbeURL: %v", err)
+	}
+
+	if !strings.HasPrefix(first, "https://example.com/gopherseo-probe-") {
+		t.Fatalf("probe URL %q is not under root", first)
+	}
+	if strings.Contains(first, "?") {
+		t.Fatalf("probe URL %q should not keep the root query", first)
+	}
+	if first == second {
+		t.Fatal("expected probe paths to be random")
+	}
+}
+
+// errorFingerprint is the fingerprint of a shop's error page that echoes the
+// requested path.
+func errorFingerprint(t *testing.T) *Fingerprint {
+	t.Helper()
+	return NewFingerprint("https://example.com/gopherseo-probe-abc", docFromHTML(t, `<html><head><title>Page Not Found | Shop</title></head><body>
+		<h1>Error 404</h1>
+		<p>Sorry, we could not find /gopherseo-probe-abc on this shop.</p>
+		<a href="/">Back to shop</a>
+	</body></html>`))
+}
+
+func TestDetect_TitlePattern(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><title>Page Not Found | Shop</title></head><body>
+		<p>Sorry, we could not find /old-item on this shop.</p>
+		<a href="/">Back to shop</a>
+	</body></html>`)
+
+	reason, ok := Detect("https://example.com/old-item", doc, errorFingerprint(t))
+	if !ok {
+		t.Fatal("expected soft 404 for not-found title resembling the error page")
+	}
+	if !strings.Contains(reason, "title") {
+		t.Fatalf("reason=%q, want title signal", reason)
+	}
+}
+
+func TestDetect_H1Pattern(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><title>Shop</title></head><body>
+		<h1>Error 404</h1>
+		<p>Sorry, we could not find /old on this shop.</p>
+	</body></html>`)
+
+	reason, ok := Detect("https://example.com/old", doc, errorFingerprint(t))
+	if !ok || !strings.Contains(reason, "h1") {
+		t.Fatalf("expected soft 404 for 404 h1 resembling the error page, got %q, %v", reason, ok)
+	}
+}
+
+func TestDetect_PhraseWithoutFingerprintAgreement(t *testing.T) {
+	pages := map[string]string{
+		"https://example.com/guides/404": `<html><head><title>404 error guide</title></head><body>
+			<h1>How to fix 404 errors</h1><p>Broken links hurt rankings. Here is how to find and redirect them.</p>
+		</body></html>`,
+		"https://example.com/lost-and-found": `<html><head><title>Lost &amp; Not Found</title></head><body>
+			<p>Our new album is out now on every streaming platform.</p>
+		</body></html>`,
+	}
+
+	for pageURL, html := range pages {
+		if reason, ok := Detect(pageURL, docFromHTML(t, html), errorFingerprint(t)); ok {
+			t.Errorf("%s: unexpected soft 404: %s", pageURL, reason)
+		}
+		if reason, ok := Detect(pageURL, docFromHTML(t, html), nil); ok {
+			t.Errorf("%s: unexpected soft 404 without a fingerprint: %s", pageURL, reason)
+		}
+	}
+}
+
+func TestDetect_NoFingerprint(t *testing.T) {
+	doc := docFromHTML(t, `<html><head><title>Page Not Found</title></head><body><h1>404</h1></body></html>`)
+
+	if reason, ok := Detect("https://example.com/missing", doc, nil); ok {
+		t.Fatalf("phrases alone should not flag a page, got %s", reason)
+	}
+}
+
+func TestDetect_FingerprintMatch(t *testing.T) {
+	errorPage := `<html><head><title>Shop</title></head><body>
+		<p>Sorry, we looked everywhere but this item is gone.</p>
+		<a href="/">Back to shop</a>
+	</body></html>`
+
+	fp := NewFingerprint("https://example.com/gopherseo-probe-abc", docFromHTML(t, errorPage))
+
+	if _, ok := Detect("https://example.com/old-item", docFromHTML(t, errorPage), fp); !ok {
+		t.Fatal("expected page matching fingerprint to be a soft 404")
+	}
+
+	other := docFromHTML(t, `<html><head><title>Shop</title></head><body>
+		<p>The best shoes for running in winter conditions.</p>
+	</body></html>`)
+	if _, ok := Detect("https://example.com/shoes", other, fp); ok {
+		t.Fatal("unrelated page should not match fingerprint")
+	}
+}
+
+func TestDetect_FingerprintIgnoresProbeTarget(t *testing.T) {
+	home := `<html><head><title>Shop</title></head><body><p>Welcome to the shop</p></body></html>`
+
+	// The probe was redirected to the homepage; the homepage itself must not
+	// be flagged.
+	fp := NewFingerprint("https://example.com/", docFromHTML(t, home))
+
+	if _, ok := Detect("https://example.com/", docFromHTML(t, home), fp); ok {
+		t.Fatal("probe redirect target should not be flagged")
+	}
+}
+
+func TestDetect_NilDocument(t *testing.T) {
+	if _, ok := Detect("https://example.com/", nil, nil); ok {
+		t.Fatal("nil document should not be flagged")
+	}
+}
@@ -0,0 +1,327 @@
+// Package throttle paces crawler requests: a minimum per-host delay with
+// random jitter, pauses on 429/503 responses that honor Retry-After, and an
+// adaptive concurrency limit that shrinks when response times rise and grows
+// back when they recover.
+package throttle
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults applied by New for zero Options fields.
+const (
+	DefaultMaxRetries = 3
+	DefaultMaxBackoff = time.Minute
+)
+
+// Adaptive concurrency tuning.
+const (
+	// ewmaWeight is the weight of the newest sample in the response time
+	// moving average.
+	ewmaWeight = 0.2
+	// warmupSamples is the number of responses before a baseline is set.
+	warmupSamples = 5
+	// adjustEvery is the number of responses between concurrency changes.
+	adjustEvery = 10
+	// slowFactor is the ratio to the baseline above which concurrency is
+	// halved.
+	slowFactor = 2.0
+	// recoverFactor is the ratio to the baseline below which concurrency is
+	// raised again.
+	recoverFactor = 1.25
+)
+
+// Options configures a Throttle.
+type Options struct {
+	// Delay is the minimum time between the start of two requests to the same
+	// host.
+	Delay time.Duration
+	// Jitter adds a random extra delay of up to Jitter to every request.
+	Jitter time.Duration
+	// Concurrency is the initial and maximum number of requests in flight.
+	// Zero means unlimited, which disables adaptive concurrency.
+	Concurrency int
+	// Adaptive halves the concurrency limit when response times rise to
+	// twice their baseline and raises it again when they recover.
+	Adaptive bool
+	// MaxRetries is how often a request answered with 429 or 503 is retried.
+	// Zero uses DefaultMaxRetries; a negative value disables retries.
+	MaxRetries int
+	// MaxBackoff caps the pause after a 429 or 503 response, including
+	// Retry-After values. Zero uses DefaultMaxBackoff.
+	MaxBackoff time.Duration
+	// OnEvent, when set, is called for every throttling decision. It may be
+	// called from several goroutines while the Throttle is locked and must
+	// not call back into it.
+	OnEvent func(Event)
+}
+
+// EventType describes a throttling decision.
+type EventType string
+
+const (
+	// EventDelay is the per-host delay chosen for a crawl, e.g. from a
+	// robots.txt Crawl-delay. Throttle itself does not emit it.
+	EventDelay EventType = "delay"
+	// EventBackoff is a host pause after a 429 or 503 response.
+	EventBackoff EventType = "backoff"
+	// EventConcurrency is a change of the concurrency limit.
+	EventConcurrency EventType = "concurrency"
+)
+
+// Event is a throttling decision reported through Options.OnEvent.
+type Event struct {
+	Type EventType
+	Host string
+	// Wait is the pause for EventBackoff.
+	Wait time.Duration
+	// Concurrency is the new limit for EventConcurrency and the current one
+	// otherwise.
+	Concurrency int
+	// Detail is a human-readable description of the decision.
+	Detail string
+}
+
+// Throttle paces requests. Callers wrap every request attempt in Acquire and
+// Release. A Throttle is safe for concurrent use.
+type Throttle struct {
+	opts Options
+
+	mu    sync.Mutex
+	cond  *sync.Cond
+	hosts map[string]*hostState
+
+	limit    int
+	inFlight int
+
+	samples  int
+	ewma     float64
+	baseline float64
+	sinceAdj int
+}
+
+type hostState struct {
+	// next is the earliest start of the next request.
+	next time.Time
+	// pausedUntil is set by a 429/503 backoff.
+	pausedUntil time.Time
+}
+
+// New returns a Throttle for opts.
+func New(opts Options) *Throttle {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	t := &Throttle{
+		opts:  opts,
+		hosts: make(map[string]*hostState),
+		limit: opts.Concurrency,
+	}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// Concurrency returns the current concurrency limit, zero when unlimited.
+func (t *Throttle) Concurrency() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
+
+// SetDelay changes the minimum per-host delay, e.g. once a robots.txt
+// Crawl-delay is known. It applies from the next request on.
+func (t *Throttle) SetDelay(delay time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.opts.Delay = delay
+}
+
+// Acquire blocks until a request to host may start: a concurrency slot is
+// free, the host's delay since the previous request has passed and no
+// backoff pause is active.
+func (t *Throttle) Acquire(host string) {
+	t.mu.Lock()
+	for t.limit > 0 && t.inFlight >= t.limit {
+		t.cond.Wait()
+	}
+	t.inFlight++
+
+	state := t.host(host)
+	for {
+		now := time.Now()
+		start := now
+		if state.next.After(start) {
+			start = state.next
+		}
+		if state.pausedUntil.After(start) {
+			start = state.pausedUntil
+		}
+		if !start.After(now) {
+			state.next = now.Add(t.opts.Delay + t.jitter())
+			t.mu.Unlock()
+			return
+		}
+		// Reserve the slot, then re-check after sleeping in case a backoff
+		// started meanwhile.
+		state.next = start.Add(t.opts.Delay + t.jitter())
+		t.mu.Unlock()
+		time.Sleep(start.Sub(now))
+		t.mu.Lock()
+		if !state.pausedUntil.After(time.Now()) {
+			t.mu.Unlock()
+			return
+		}
+	}
+}
+
+// AcquireAfter is Acquire for a request that must not start before
+// notBefore, such as a retry waiting out its backoff. The wait holds no
+// concurrency slot, so other requests proceed meanwhile.
+func (t *Throttle) AcquireAfter(host string, notBefore time.Time) {
+	if wait := time.Until(notBefore); wait > 0 {
+		time.Sleep(wait)
+	}
+	t.Acquire(host)
+}
+
+// Release ends a request started with Acquire. elapsed is the response time,
+// used for adaptive concurrency. For a 429 or 503 response the host is
+// paused for its Retry-After value (or an exponential backoff without one)
+// and the concurrency limit is halved; Release then reports whether the
+// request should be retried. attempt is the number of retries already made.
+func (t *Throttle) Release(host string, elapsed time.Duration, status int, header http.Header, attempt int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.inFlight--
+	t.cond.Broadcast()
+
+	if status != http.StatusTooManyRequests && status != http.StatusServiceUnavailable {
+		if status > 0 && status < 500 {
+			t.observe(elapsed)
+		}
+		return false
+	}
+
+	wait, fromHeader := retryAfter(header, time.Now())
+	if !fromHeader {
+		wait = time.Second << min(attempt, 10)
+	}
+	if wait > t.opts.MaxBackoff {
+		wait = t.opts.MaxBackoff
+	}
+	state := t.host(host)
+	if until := time.Now().Add(wait); until.After(state.pausedUntil) {
+		state.pausedUntil = until
+	}
+
+	source := "exponential backoff"
+	if fromHeader {
+		source = "Retry-After"
+	}
+	t.emit(Event{Type: EventBackoff, Host: host, Wait: wait, Concurrency: t.limit,
+		Detail: fmt.Sprintf("HTTP %d: pausing %s for %s (%s)", status, host, wait, source)})
+
+	if t.opts.Adaptive && t.limit > 1 {
+		t.setLimit(t.limit/2, "server asked to slow down")
+	}
+	return t.opts.MaxRetries > 0 && attempt < t.opts.MaxRetries
+}
+
+// observe feeds a response time into the moving average and adjusts the
+// concurrency limit every adjustEvery responses.
+func (t *Throttle) observe(elapsed time.Duration) {
+	if !t.opts.Adaptive || t.opts.Concurrency <= 0 {
+		return
+	}
+
+	sample := float64(elapsed)
+	t.samples++
+	if t.samples == 1 {
+		t.ewma = sample
+	} else {
+		t.ewma = ewmaWeight*sample + (1-ewmaWeight)*t.ewma
+	}
+	if t.samples < warmupSamples {
+		return
+	}
+	if t.baseline == 0 || t.ewma < t.baseline {
+		t.baseline = t.ewma
+	}
+
+	t.sinceAdj++
+	if t.sinceAdj < adjustEvery {
+		return
+	}
+	t.sinceAdj = 0
+
+	avg := time.Duration(t.ewma).Round(time.Millisecond)
+	switch {
+	case t.ewma > slowFactor*t.baseline && t.limit > 1:
+		t.setLimit(t.limit/2, fmt.Sprintf("response time rose to %s", avg))
+	case t.ewma < recoverFactor*t.baseline && t.limit < t.opts.Concurrency:
+		t.setLimit(t.limit+1, fmt.Sprintf("response time recovered to %s", avg))
+	}
+}
+
+// setLimit changes the concurrency limit; t.mu must be held.
+func (t *Throttle) setLimit(limit int, reason string) {
+	limit = max(limit, 1)
+	if limit == t.limit {
+		return
+	}
+	t.limit = limit
+	t.cond.Broadcast()
+	t.emit(Event{Type: EventConcurrency, Concurrency: limit,
+		Detail: fmt.Sprintf("concurrency set to %d: %s", limit, reason)})
+}
+
+func (t *Throttle) emit(e Event) {
+	if t.opts.OnEvent != nil {
+		t.opts.OnEvent(e)
+	}
+}
+
+func (t *Throttle) host(host string) *hostState {
+	state, ok := t.hosts[host]
+	if !ok {
+		state = &hostState{}
+		t.hosts[host] = state
+	}
+	return state
+}
+
+func (t *Throttle) jitter() time.Duration {
+	if t.opts.Jitter <= 0 {
+		return 0
+	}
+	return rand.N(t.opts.Jitter)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date. The boolean is false when the header is missing or invalid.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,182 @@
+package throttle
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquire_DelaysSameHost(t *testing.T) {
+	th := New(Options{Delay: 50 * time.Millisecond})
+
+	start := time.Now()
+	th.Acquire("example.com")
+	th.Release("example.com", 0, http.StatusOK, nil, 0)
+	th.Acquire("other.example")
+	th.Release("other.example", 0, http.StatusOK, nil, 0)
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Fatalf("different hosts should not wait for each other, took %v", elapsed)
+	}
+
+	th.Acquire("example.com")
+	th.Release("example.com", 0, http.StatusOK, nil, 0)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("second request to the same host started after %v, want >= 50ms", elapsed)
+	}
+}
+
+func TestRelease_BackoffHonorsRetryAfter(t *testing.T) {
+	var mu sync.Mutex
+	var events []Event
+	th := New(Options{
+		Concurrency: 4,
+		Adaptive:    true,
+		MaxRetries:  2,
+		OnEvent: func(e Event) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		},
+	})
+
+	header := http.Header{}
+	header.Set("Retry-After", "0")
+
+	th.Acquire("example.com")
+	if !th.Release("example.com", 0, http.StatusTooManyRequests, header, 0) {
+		t.Error("first 429 should be retried")
+	}
+	th.Acquire("example.com")
+	if th.Release("example.com", 0, http.StatusServiceUnavailable, header, 2) {
+		t.Error("503 after MaxRetries attempts should not be retried")
+	}
+	if got := th.Concurrency(); got != 1 {
+		t.Errorf("Concurrency=%d after two backoffs, want 1", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) == 0 || events[0].Type != EventBackoff || events[0].Wait != 0 {
+		t.Errorf("expected a zero-wait backoff event first, got %+v", events)
+	}
+}
+
+func TestRelease_PausesHost(t *testing.T) {
+	th := New(Options{MaxBackoff: 80 * time.Millisecond})
+
+	header := http.Header{}
+	header.Set("Retry-After", "3600")
+	th.Acquire("example.com")
+	th.Release("example.com", 0, http.StatusTooManyRequests, header, 0)
+
+	start := time.Now()
+	th.Acquire("example.com")
+	th.Release("example.com", 0, http.StatusOK, nil, 0)
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond || elapsed > time.Second {
+		t.Errorf("paused request waited %v, want about the 80ms MaxBackoff cap", elapsed)
+	}
+}
+
+func TestObserve_AdaptsConcurrency(t *testing.T) {
+	th := New(Options{Concurrency: 8, Adaptive: true})
+
+	for i := 0; i < warmupSamples+adjustEvery; i++ {
+		th.Acquire("example.com")
+		th.Release("example.com", 10*time.Millisecond, http.StatusOK, nil, 0)
+	}
+	if got := th.Concurrency(); got != 8 {
+		t.Fatalf("Concurrency=%d with steady response times, want 8", got)
+	}
+
+	for i := 0; i < adjustEvery; i++ {
+		th.Acquire("example.com")
+		th.Release("example.com", 100*time.Millisecond, http.StatusOK, nil, 0)
+	}
+	if got := th.Concurrency(); got != 4 {
+		t.Fatalf("Concurrency=%d after slow responses, want 4", got)
+	}
+
+	for i := 0; i < 4*adjustEvery; i++ {
+		th.Acquire("example.com")
+		th.Release("example.com", 10*time.Millisecond, http.StatusOK, nil, 0)
+	}
+	if got := th.Concurrency(); got <= 4 {
+		t.Errorf("Concurrency=%d after recovery, want > 4", got)
+	}
+}
+
+func TestAcquire_LimitsInFlight(t *testing.T) {
+	th := New(Options{Concurrency: 1})
+	th.Acquire("example.com")
+
+	acquired := make(chan struct{})
+	go func() {
+		th.Acquire("example.com")
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Acquire should block while the only slot is taken")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	th.Release("example.com", 0, http.StatusOK, nil, 0)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second Acquire should proceed after Release")
+	}
+}
+
+func TestAcquireAfter_WaitsWithoutSlot(t *testing.T) {
+	th := New(Options{Concurrency: 1})
+
+	const backoff = 60 * time.Millisecond
+	start := time.Now()
+	done := make(chan time.Duration)
+	go func() {
+		th.AcquireAfter("example.com", start.Add(backoff))
+		done <- time.Since(start)
+		th.Release("example.com", 0, http.StatusOK, nil, 0)
+	}()
+
+	// The only slot stays free while the retry waits.
+	th.Acquire("example.com")
+	if elapsed := time.Since(start); elapsed >= backoff {
+		t.Errorf("Acquire waited %v behind a backing-off request", elapsed)
+	}
+	th.Release("example.com", 0, http.StatusOK, nil, 0)
+
+	if elapsed := <-done; elapsed < backoff {
+		t.Errorf("AcquireAfter returned after %v, want >= %v", elapsed, backoff)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Thu, 02 Jan 2025 10:00:30 GMT", 30 * time.Second, true},
+		{"Thu, 02 Jan 2025 09:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		got, ok := retryAfter(header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v,%v, want %v,%v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package throttle
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Transport is an http.RoundTripper that wraps every request in Acquire and
+// Release, so requests made outside the crawler's collector (robots.txt,
+// soft-404 probe, image checks, form login) follow the same delay, backoff
+// and concurrency limit. Requests answered with 429 or 503 are retried as
+// long as Release allows it.
+type Transport struct {
+	// Base performs the requests. Nil uses http.DefaultTransport.
+	Base     http.RoundTripper
+	Throttle *Throttle
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		t.Throttle.Acquire(req.URL.Host)
+		started := time.Now()
+		resp, err := base.RoundTrip(req)
+
+		status, header := 0, http.Header(nil)
+		if err == nil {
+			status, header = resp.StatusCode, resp.Header
+		}
+		retry := t.Throttle.Release(req.URL.Host, time.Since(started), status, header, attempt)
+		// A request body that cannot be replayed is not retried.
+		if err != nil || !retry || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return resp, err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
@@ -0,0 +1,61 @@
+package throttle
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTransport_DelaysAndRetries(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		starts = append(starts, time.Now())
+		bodies = append(bodies, string(body))
+		first := len(starts) == 1
+		mu.Unlock()
+		if first {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: &Transport{Throttle: New(Options{Delay: 40 * time.Millisecond})}}
+
+	resp, err := client.Post(ts.URL+"/login", "text/plain", strings.NewReader("user=a"))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want the 429 to be retried", resp.StatusCode)
+	}
+
+	resp, err = client.Get(ts.URL + "/image.png")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(starts) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(starts))
+	}
+	if bodies[0] != "user=a" || bodies[1] != "user=a" {
+		t.Errorf("retried request bodies = %q, want the body replayed", bodies[:2])
+	}
+	if gap := starts[2].Sub(starts[1]); gap < 35*time.Millisecond {
+		t.Errorf("requests %v apart, want the 40ms delay", gap)
+	}
+}
@@ -0,0 +1,66 @@
+// Package urlglob matches URLs against the glob patterns accepted by
+// --exclude and the project config, e.g. "*/print/*", "/admin/*", "*.pdf" or
+// "*?lang=rs".
+package urlglob
+
+import (
+	"net/url"
+	pathpkg "path"
+	"strings"
+)
+
+// Match reports whether link matches pattern. The pattern is tried against
+// the full URL, the path, the file name, and the path plus query with and
+// without the leading slash. An empty pattern matches nothing.
+func Match(pattern, link string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+
+	// Use path.Match (not filepath.Match) so glob behaviour is consistent
+	// across operating systems — URL paths always use forward slashes.
+	if matched, _ := pathpkg.Match(pattern, link); matched {
+		return true
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	// Match against the full path (e.g. /admin/*).
+	if matched, _ := pathpkg.Match(pattern, parsed.Path); matched {
+		return true
+	}
+
+	// Match against just the filename so *.pdf matches /dir/file.pdf.
+	if matched, _ := pathpkg.Match(pattern, pathpkg.Base(parsed.Path)); matched {
+		return true
+	}
+
+	// Match path+query with and without the leading slash so that
+	// patterns like *?lang=rs work against page?lang=rs.
+	if parsed.RawQuery != "" {
+		queryPath := parsed.Path + "?" + parsed.RawQuery
+		if matched, _ := pathpkg.Match(pattern, queryPath); matched {
+			return true
+		}
+		trimmed := strings.TrimPrefix(queryPath, "/")
+		if matched, _ := pathpkg.Match(pattern, trimmed); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchAny reports whether link matches any of patterns.
+func MatchAny(link string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, link) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package urlglob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		link    string
+		want    bool
+	}{
+		{"*/print/*", "https://example.com/print/page", true},
+		{"/admin/*", "https://example.com/admin/users", true},
+		{"*.pdf", "https://example.com/docs/guide.pdf", true},
+		{"*?lang=rs", "https://example.com/page?lang=rs", true},
+		{"/blog/*", "https://example.com/docs/page", false},
+		{"  ", "https://example.com/", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.link); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	if !MatchAny("https://example.com/a.pdf", []string{"/admin/*", "*.pdf"}) {
+		t.Error("expected a match")
+	}
+	if MatchAny("https://example.com/a", nil) {
+		t.Error("no patterns should match nothing")
+	}
+}
@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	// Embed the time zone database so --timezone works on systems without
+	// zoneinfo files (e.g. Windows).
+	_ "time/tzdata"
 
 	"github.com/tariktz/gopherseo/cmd"
 )